@@ -0,0 +1,83 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StrongETag devuelve un ETag fuerte ("...") derivado de parts. Sirve para
+// representaciones que cambian byte a byte solo cuando cambia alguna de las partes.
+func StrongETag(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// WeakETag devuelve un ETag débil (W/"...") derivado de parts, para representaciones
+// equivalentes pero no necesariamente idénticas byte a byte (por ejemplo, listados).
+func WeakETag(parts ...string) string {
+	return "W/" + StrongETag(parts...)
+}
+
+// NotModified añade ETag y Last-Modified a la respuesta y, si la petición condicional
+// indica que el cliente ya tiene esta versión, responde 304 y devuelve true.
+// Como indica RFC 9110, If-Modified-Since solo se evalúa si no hay If-None-Match.
+// lastModified puede ser cero si la representación no tiene fecha.
+func NotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	notModified := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		notModified = etagListMatches(inm, etag, false)
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		// HTTP-date tiene precisión de segundos
+		notModified = err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+
+	if notModified {
+		w.WriteHeader(http.StatusNotModified)
+	}
+	return notModified
+}
+
+// IfMatch evalúa el header If-Match contra el ETag actual del recurso.
+// present es false si la petición no lo incluye; matches usa la comparación fuerte,
+// así que los ETags débiles nunca coinciden, y "*" coincide con cualquier ETag.
+func IfMatch(r *http.Request, etag string) (present, matches bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return false, false
+	}
+	return true, etagListMatches(header, etag, true)
+}
+
+// etagListMatches indica si etag aparece en la lista separada por comas de un header
+// If-Match o If-None-Match. La comparación débil ignora el prefijo W/.
+func etagListMatches(list, etag string, strong bool) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strong {
+			if !strings.HasPrefix(candidate, "W/") && candidate == etag {
+				return true
+			}
+			continue
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
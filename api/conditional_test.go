@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+	etag := StrongETag("chirp", "1")
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{"No conditional headers", nil, false},
+		{"Matching If-None-Match", map[string]string{"If-None-Match": etag}, true},
+		{"Weak comparison ignores W/", map[string]string{"If-None-Match": "W/" + etag}, true},
+		{"Any of a list", map[string]string{"If-None-Match": `"other", ` + etag}, true},
+		{"Different ETag", map[string]string{"If-None-Match": `"other"`}, false},
+		{"Not modified since", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, true},
+		{"Modified since", map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, false},
+		{
+			"If-None-Match takes precedence",
+			map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": modified.Format(http.TimeFormat)},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/chirps", nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+
+			if got := NotModified(w, r, etag, modified); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+			if w.Header().Get("ETag") != etag {
+				t.Errorf("ETag header = %q, want %q", w.Header().Get("ETag"), etag)
+			}
+			if tt.want && w.Code != http.StatusNotModified {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNotModified)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	etag := StrongETag("chirp", "1")
+
+	tests := []struct {
+		header              string
+		wantPresent, wantOK bool
+	}{
+		{"", false, false},
+		{etag, true, true},
+		{"*", true, true},
+		{"W/" + etag, true, false},
+		{`"other"`, true, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("DELETE", "/api/chirps/1", nil)
+		if tt.header != "" {
+			r.Header.Set("If-Match", tt.header)
+		}
+		present, ok := IfMatch(r, etag)
+		if present != tt.wantPresent || ok != tt.wantOK {
+			t.Errorf("IfMatch(%q) = %v, %v, want %v, %v", tt.header, present, ok, tt.wantPresent, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+)
+
+// StatusForDBError traduce un error de base de datos al código HTTP que le corresponde.
+func StatusForDBError(err error) int {
+	err = database.ClassifyError(err)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, database.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, database.ErrReference), errors.Is(err, database.ErrConstraint):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RespondWithDBError responde a un error de base de datos con el status adecuado.
+// notFound es el código que se usa si el registro no existe (por ejemplo CodeChirpNotFound).
+// El error del driver solo se registra en el log.
+func RespondWithDBError(w http.ResponseWriter, r *http.Request, notFound Code, err error) {
+	status := StatusForDBError(err)
+	switch status {
+	case http.StatusNotFound:
+		RespondWithError(w, r, status, notFound, nil)
+	case http.StatusConflict:
+		RespondWithError(w, r, status, CodeConflict, err)
+	case http.StatusUnprocessableEntity:
+		RespondWithError(w, r, status, CodeConstraintViolation, err)
+	default:
+		RespondWithError(w, r, status, CodeInternalError, err)
+	}
+}
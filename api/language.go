@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NegotiateLanguage elige el idioma soportado con mayor peso en un header Accept-Language.
+// Acepta rangos como "es-MX" (se reduce a "es") y "*"; si nada coincide devuelve DefaultLanguage.
+func NegotiateLanguage(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(tag, "-")
+		if primary == "*" {
+			primary = DefaultLanguage
+		}
+		if _, ok := messages[primary]; ok {
+			candidates = append(candidates, candidate{lang: primary, q: q})
+		}
+	}
+
+	// Ante el mismo peso se respeta el orden en que los envió el cliente
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	return candidates[0].lang
+}
+
+// RequestLanguage devuelve el idioma negociado para la petición.
+func RequestLanguage(r *http.Request) string {
+	return NegotiateLanguage(r.Header.Get("Accept-Language"))
+}
@@ -0,0 +1,65 @@
+package api
+
+import "testing"
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "Empty header",
+			header: "",
+			want:   LangEnglish,
+		},
+		{
+			name:   "Spanish with region",
+			header: "es-MX",
+			want:   LangSpanish,
+		},
+		{
+			name:   "Quality values",
+			header: "en;q=0.5, es;q=0.9",
+			want:   LangSpanish,
+		},
+		{
+			name:   "Unsupported languages fall back",
+			header: "fr-FR, de;q=0.8",
+			want:   LangEnglish,
+		},
+		{
+			name:   "Unsupported first, supported later",
+			header: "fr, es;q=0.3",
+			want:   LangSpanish,
+		},
+		{
+			name:   "Zero quality is ignored",
+			header: "es;q=0, en;q=0.1",
+			want:   LangEnglish,
+		},
+		{
+			name:   "Same quality keeps client order",
+			header: "es, en",
+			want:   LangSpanish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NegotiateLanguage(tt.header); got != tt.want {
+				t.Errorf("NegotiateLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for code := range messages[DefaultLanguage] {
+		for lang, catalog := range messages {
+			if _, ok := catalog[code]; !ok {
+				t.Errorf("missing %q translation for %s", lang, code)
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+// Idiomas soportados por el catálogo de mensajes.
+const (
+	LangEnglish = "en"
+	LangSpanish = "es"
+)
+
+// DefaultLanguage se usa cuando el cliente no pide ningún idioma soportado.
+const DefaultLanguage = LangEnglish
+
+// messages es el catálogo de textos legibles (detail) de cada código, por idioma.
+var messages = map[string]map[Code]string{
+	LangEnglish: {
+		CodeInternalError:       "Internal server error",
+		CodeInvalidPayload:      "Invalid request payload",
+		CodeValidationFailed:    "The request has invalid fields",
+		CodeNotFound:            "Resource not found",
+		CodeConflict:            "The resource conflicts with an existing one",
+		CodeConstraintViolation: "The request violates a data constraint",
+		CodeRequired:            "This field is required",
+		CodeInvalidPagination:   "Invalid pagination parameters",
+		CodeServiceUnavailable:  "The service is temporarily unavailable, try again later",
+
+		CodePreconditionRequired: "This request requires an If-Match header with the resource's ETag",
+		CodePreconditionFailed:   "The resource was modified since you last fetched it",
+
+		CodeRateLimited: "Too many requests, try again later",
+
+		CodeInvalidIdempotencyKey:    "Idempotency-Key must be at most 255 characters",
+		CodeIdempotencyKeyReused:     "This Idempotency-Key was already used with a different request",
+		CodeIdempotencyKeyInProgress: "A request with this Idempotency-Key is still being processed",
+
+		CodeInvalidMessageType: "Unknown message type",
+		CodeInvalidTopic:       "Unknown topic; use global, user:<id>, hashtag:<tag> or notifications",
+		CodeTooManyTopics:      "Too many subscriptions on this connection",
+
+		CodeMissingToken:            "Authorization token is missing",
+		CodeInvalidToken:            "Authorization token is invalid or expired",
+		CodeInvalidRefreshToken:     "Refresh token is invalid, expired or revoked",
+		CodeInvalidCredentials:      "Incorrect email or password",
+		CodeIncorrectPassword:       "Current password is incorrect",
+		CodeInvalidAPIKey:           "Invalid API key",
+		CodeInsufficientPermissions: "Insufficient permissions",
+		CodeActionNotAllowed:        "Action not allowed in this environment",
+
+		CodeAccountBanned:          "Account is banned",
+		CodeAccountSuspended:       "Account is suspended",
+		CodeAccountPendingDeletion: "Account is scheduled for deletion",
+		CodeAccountAlreadyDeleted:  "Account is already scheduled for deletion",
+
+		CodeUserNotFound:             "User not found",
+		CodeInvalidUserID:            "Invalid user ID format",
+		CodeEmailInUse:               "Email is already in use",
+		CodeAccountExists:            "Email or username is already in use",
+		CodeUsernameTaken:            "Username is already taken",
+		CodeUsernameInvalid:          "Username must be 3-30 letters, digits or underscores",
+		CodeUsernameReserved:         "Username is reserved",
+		CodeDisplayNameTooLong:       "Display name is too long",
+		CodeBioTooLong:               "Bio is too long",
+		CodeAvatarURLTooLong:         "Avatar URL is too long",
+		CodeAvatarURLInvalid:         "Avatar URL must be an http(s) URL",
+		CodeInvalidEmail:             "Invalid email address",
+		CodePasswordEmpty:            "Password can't be empty",
+		CodeNoFieldsToUpdate:         "No fields to update",
+		CodeInvalidVerificationToken: "Invalid or expired verification token",
+		CodeCannotTargetSelf:         "You can't do that to yourself",
+		CodeBlockedByUser:            "This user has blocked you",
+		CodeSignupsDisabled:          "New sign-ups are currently disabled",
+
+		CodeChirpNotFound:   "Chirp not found",
+		CodeInvalidChirpID:  "Invalid chirp ID format",
+		CodeChirpTooLong:    "Chirp is too long",
+		CodeNotChirpOwner:   "You are not the owner of this chirp",
+		CodeMentionBlocked:  "You can't mention a user who blocked you",
+		CodeInvalidAuthorID: "Invalid author ID",
+
+		CodeReportNotFound:          "Report not found",
+		CodeInvalidReportID:         "Invalid report ID format",
+		CodeInvalidReportReason:     "Invalid report reason",
+		CodeReportDetailsTooLong:    "Report details are too long",
+		CodeReportAlreadyResolved:   "Report is already resolved",
+		CodeReportHasNoChirp:        "Report has no chirp attached",
+		CodeInvalidModerationAction: "Invalid moderation action",
+		CodeInvalidReportStatus:     "Invalid report status",
+		CodeInvalidRole:             "Invalid role",
+		CodeInvalidSuspension:       "Suspension hours must be positive",
+		CodeCannotModerateUser:      "You can only moderate users whose role is below yours",
+
+		CodeNotificationNotFound:    "Notification not found",
+		CodeInvalidNotificationID:   "Invalid notification ID format",
+		CodeInvalidNotificationType: "Invalid notification type",
+	},
+	LangSpanish: {
+		CodeInternalError:       "Error interno del servidor",
+		CodeInvalidPayload:      "El cuerpo de la petición no es válido",
+		CodeValidationFailed:    "La petición tiene campos no válidos",
+		CodeNotFound:            "Recurso no encontrado",
+		CodeConflict:            "El recurso entra en conflicto con uno existente",
+		CodeConstraintViolation: "La petición viola una restricción de datos",
+		CodeRequired:            "Este campo es obligatorio",
+		CodeInvalidPagination:   "Parámetros de paginación no válidos",
+		CodeServiceUnavailable:  "El servicio no está disponible por ahora, inténtalo más tarde",
+
+		CodePreconditionRequired: "Esta petición requiere un header If-Match con el ETag del recurso",
+		CodePreconditionFailed:   "El recurso cambió desde la última vez que lo obtuviste",
+
+		CodeRateLimited: "Demasiadas peticiones, inténtalo más tarde",
+
+		CodeInvalidIdempotencyKey:    "Idempotency-Key debe tener como máximo 255 caracteres",
+		CodeIdempotencyKeyReused:     "Esta Idempotency-Key ya se usó con otra petición",
+		CodeIdempotencyKeyInProgress: "Una petición con esta Idempotency-Key todavía se está procesando",
+
+		CodeInvalidMessageType: "Tipo de mensaje desconocido",
+		CodeInvalidTopic:       "Tema desconocido; usa global, user:<id>, hashtag:<tag> o notifications",
+		CodeTooManyTopics:      "Demasiadas suscripciones en esta conexión",
+
+		CodeMissingToken:            "No se encontró el token de autorización",
+		CodeInvalidToken:            "El token de autorización no es válido o ha expirado",
+		CodeInvalidRefreshToken:     "El refresh token no es válido, ha expirado o fue revocado",
+		CodeInvalidCredentials:      "Email o contraseña incorrectos",
+		CodeIncorrectPassword:       "La contraseña actual es incorrecta",
+		CodeInvalidAPIKey:           "API key no válida",
+		CodeInsufficientPermissions: "Permisos insuficientes",
+		CodeActionNotAllowed:        "Acción no permitida en este entorno",
+
+		CodeAccountBanned:          "La cuenta está baneada",
+		CodeAccountSuspended:       "La cuenta está suspendida",
+		CodeAccountPendingDeletion: "La cuenta está programada para eliminarse",
+		CodeAccountAlreadyDeleted:  "La cuenta ya está programada para eliminarse",
+
+		CodeUserNotFound:             "Usuario no encontrado",
+		CodeInvalidUserID:            "Formato de ID de usuario no válido",
+		CodeEmailInUse:               "El email ya está en uso",
+		CodeAccountExists:            "El email o el username ya están en uso",
+		CodeUsernameTaken:            "El username ya está en uso",
+		CodeUsernameInvalid:          "El username debe tener de 3 a 30 letras, dígitos o guiones bajos",
+		CodeUsernameReserved:         "El username está reservado",
+		CodeDisplayNameTooLong:       "El nombre visible es demasiado largo",
+		CodeBioTooLong:               "La bio es demasiado larga",
+		CodeAvatarURLTooLong:         "La URL del avatar es demasiado larga",
+		CodeAvatarURLInvalid:         "La URL del avatar debe ser http(s)",
+		CodeInvalidEmail:             "Dirección de email no válida",
+		CodePasswordEmpty:            "La contraseña no puede estar vacía",
+		CodeNoFieldsToUpdate:         "No hay campos para actualizar",
+		CodeInvalidVerificationToken: "Token de verificación no válido o expirado",
+		CodeCannotTargetSelf:         "No puedes hacer eso contigo mismo",
+		CodeBlockedByUser:            "Este usuario te bloqueó",
+		CodeSignupsDisabled:          "El registro de nuevos usuarios está desactivado",
+
+		CodeChirpNotFound:   "Chirp no encontrado",
+		CodeInvalidChirpID:  "Formato de ID de chirp no válido",
+		CodeChirpTooLong:    "El chirp es demasiado largo",
+		CodeNotChirpOwner:   "No eres el dueño de este chirp",
+		CodeMentionBlocked:  "No puedes mencionar a un usuario que te bloqueó",
+		CodeInvalidAuthorID: "ID de autor no válido",
+
+		CodeReportNotFound:          "Reporte no encontrado",
+		CodeInvalidReportID:         "Formato de ID de reporte no válido",
+		CodeInvalidReportReason:     "Motivo de reporte no válido",
+		CodeReportDetailsTooLong:    "Los detalles del reporte son demasiado largos",
+		CodeReportAlreadyResolved:   "El reporte ya está resuelto",
+		CodeReportHasNoChirp:        "El reporte no tiene un chirp asociado",
+		CodeInvalidModerationAction: "Acción de moderación no válida",
+		CodeInvalidReportStatus:     "Estado de reporte no válido",
+		CodeInvalidRole:             "Rol no válido",
+		CodeInvalidSuspension:       "Las horas de suspensión deben ser positivas",
+		CodeCannotModerateUser:      "Solo puedes moderar a usuarios con un rol inferior al tuyo",
+
+		CodeNotificationNotFound:    "Notificación no encontrada",
+		CodeInvalidNotificationID:   "Formato de ID de notificación no válido",
+		CodeInvalidNotificationType: "Tipo de notificación no válido",
+	},
+}
+
+// Message devuelve el texto de un código en el idioma pedido.
+// Si falta la traducción se usa el idioma por defecto y, en último caso, el propio código.
+func Message(lang string, code Code) string {
+	if msg, ok := messages[lang][code]; ok {
+		return msg
+	}
+	if msg, ok := messages[DefaultLanguage][code]; ok {
+		return msg
+	}
+	return string(code)
+}
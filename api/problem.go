@@ -0,0 +1,120 @@
+package api
+
+// Code identifica de forma estable cada tipo de error que devuelve la API.
+// Los clientes deben usar el código, no el texto de detail, para decidir qué hacer.
+type Code string
+
+// problemTypePrefix se antepone al código para formar el campo "type" del problem+json.
+const problemTypePrefix = "urn:chirpy:problem:"
+
+// Errores genéricos
+const (
+	CodeInternalError       Code = "internal_error"
+	CodeInvalidPayload      Code = "invalid_payload"
+	CodeValidationFailed    Code = "validation_failed"
+	CodeNotFound            Code = "not_found"
+	CodeConflict            Code = "conflict"
+	CodeConstraintViolation Code = "constraint_violation"
+	CodeRequired            Code = "required"
+	CodeInvalidPagination   Code = "invalid_pagination"
+	CodeServiceUnavailable  Code = "service_unavailable"
+)
+
+// Peticiones condicionales
+const (
+	CodePreconditionRequired Code = "precondition_required"
+	CodePreconditionFailed   Code = "precondition_failed"
+)
+
+// Límites de uso
+const (
+	CodeRateLimited Code = "rate_limited"
+)
+
+// Claves de idempotencia
+const (
+	CodeInvalidIdempotencyKey    Code = "invalid_idempotency_key"
+	CodeIdempotencyKeyReused     Code = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress Code = "idempotency_key_in_progress"
+)
+
+// WebSocket
+const (
+	CodeInvalidMessageType Code = "invalid_message_type"
+	CodeInvalidTopic       Code = "invalid_topic"
+	CodeTooManyTopics      Code = "too_many_topics"
+)
+
+// Autenticación y permisos
+const (
+	CodeMissingToken            Code = "missing_token"
+	CodeInvalidToken            Code = "invalid_token"
+	CodeInvalidRefreshToken     Code = "invalid_refresh_token"
+	CodeInvalidCredentials      Code = "invalid_credentials"
+	CodeIncorrectPassword       Code = "incorrect_password"
+	CodeInvalidAPIKey           Code = "invalid_api_key"
+	CodeInsufficientPermissions Code = "insufficient_permissions"
+	CodeActionNotAllowed        Code = "action_not_allowed"
+)
+
+// Estado de la cuenta
+const (
+	CodeAccountBanned          Code = "account_banned"
+	CodeAccountSuspended       Code = "account_suspended"
+	CodeAccountPendingDeletion Code = "account_pending_deletion"
+	CodeAccountAlreadyDeleted  Code = "account_already_deleted"
+)
+
+// Usuarios y perfiles
+const (
+	CodeUserNotFound             Code = "user_not_found"
+	CodeInvalidUserID            Code = "invalid_user_id"
+	CodeEmailInUse               Code = "email_in_use"
+	CodeAccountExists            Code = "account_exists"
+	CodeUsernameTaken            Code = "username_taken"
+	CodeUsernameInvalid          Code = "username_invalid"
+	CodeUsernameReserved         Code = "username_reserved"
+	CodeDisplayNameTooLong       Code = "display_name_too_long"
+	CodeBioTooLong               Code = "bio_too_long"
+	CodeAvatarURLTooLong         Code = "avatar_url_too_long"
+	CodeAvatarURLInvalid         Code = "avatar_url_invalid"
+	CodeInvalidEmail             Code = "invalid_email"
+	CodePasswordEmpty            Code = "password_empty"
+	CodeNoFieldsToUpdate         Code = "no_fields_to_update"
+	CodeInvalidVerificationToken Code = "invalid_verification_token"
+	CodeCannotTargetSelf         Code = "cannot_target_self"
+	CodeBlockedByUser            Code = "blocked_by_user"
+	CodeSignupsDisabled          Code = "signups_disabled"
+)
+
+// Chirps
+const (
+	CodeChirpNotFound   Code = "chirp_not_found"
+	CodeInvalidChirpID  Code = "invalid_chirp_id"
+	CodeChirpTooLong    Code = "chirp_too_long"
+	CodeNotChirpOwner   Code = "not_chirp_owner"
+	CodeMentionBlocked  Code = "mention_blocked"
+	CodeInvalidAuthorID Code = "invalid_author_id"
+)
+
+// Reportes y moderación
+const (
+	CodeReportNotFound          Code = "report_not_found"
+	CodeInvalidReportID         Code = "invalid_report_id"
+	CodeInvalidReportReason     Code = "invalid_report_reason"
+	CodeReportDetailsTooLong    Code = "report_details_too_long"
+	CodeReportAlreadyResolved   Code = "report_already_resolved"
+	CodeReportHasNoChirp        Code = "report_has_no_chirp"
+	CodeInvalidModerationAction Code = "invalid_moderation_action"
+	CodeInvalidReportStatus     Code = "invalid_report_status"
+	CodeInvalidRole             Code = "invalid_role"
+	CodeInvalidSuspension       Code = "invalid_suspension"
+	CodeCannotModerateUser      Code = "cannot_moderate_user"
+)
+
+// Notificaciones
+const (
+	CodeNotificationNotFound    Code = "notification_not_found"
+	CodeInvalidNotificationID   Code = "invalid_notification_id"
+	CodeInvalidNotificationType Code = "invalid_notification_type"
+)
@@ -2,18 +2,86 @@ package api
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
 )
 
-func RespondWithError(w http.ResponseWriter, code int, msg string, err error) {
-	response := map[string]string{"error": msg}
+// Problem es el cuerpo de error según RFC 7807 (application/problem+json).
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      Code         `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describe por qué un campo concreto de la petición no es válido.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+}
+
+// RespondWithError responde con un problem+json para el código indicado,
+// con el detail traducido según el Accept-Language de la petición.
+// err nunca se envía al cliente: solo se registra en el log junto al request ID.
+func RespondWithError(w http.ResponseWriter, r *http.Request, status int, code Code, err error) {
 	if err != nil {
-		response["detail"] = err.Error()
+		level := slog.LevelWarn
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.Log(r.Context(), level, "request error",
+			slog.String("request_id", RequestIDFromContext(r.Context())),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.String("code", string(code)),
+			slog.Any("err", err),
+		)
+	}
+
+	respondWithProblem(w, r, newProblem(r, status, code))
+}
+
+// RespondWithValidationErrors responde 400 con el detalle de cada campo inválido.
+func RespondWithValidationErrors(w http.ResponseWriter, r *http.Request, fields ...FieldError) {
+	problem := newProblem(r, http.StatusBadRequest, CodeValidationFailed)
+	lang := RequestLanguage(r)
+	for _, field := range fields {
+		field.Message = Message(lang, field.Code)
+		problem.Errors = append(problem.Errors, field)
 	}
-	RespondWithJSON(w, code, response)
+
+	respondWithProblem(w, r, problem)
 }
 
 func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	writeJSON(w, code, "application/json", payload)
+}
+
+func newProblem(r *http.Request, status int, code Code) Problem {
+	return Problem{
+		Type:      problemTypePrefix + string(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    Message(RequestLanguage(r), code),
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: RequestIDFromContext(r.Context()),
+	}
+}
+
+func respondWithProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	w.Header().Set("Content-Language", RequestLanguage(r))
+	w.Header().Add("Vary", "Accept-Language")
+	writeJSON(w, problem.Status, "application/problem+json", problem)
+}
+
+func writeJSON(w http.ResponseWriter, code int, contentType string, payload interface{}) {
 	response, err := json.Marshal(payload)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -21,7 +89,7 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(code)
 	w.Write(response)
 }
@@ -8,11 +8,22 @@ import (
 
 // Chirp representa la estructura principal de un chirp
 type Chirp struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserID    uuid.UUID `json:"user_id"`
-	Body      string    `json:"body"`
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Body      string     `json:"body"`
+	ReplyToID *uuid.UUID `json:"reply_to_id,omitempty"`
+	Hidden    bool       `json:"hidden,omitempty"`
+	Author    *Author    `json:"author,omitempty"`
+}
+
+// Author es la versión ligera del perfil que se incluye en cada chirp
+type Author struct {
+	ID          uuid.UUID `json:"id"`
+	Username    string    `json:"username,omitempty"`
+	DisplayName string    `json:"display_name,omitempty"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
 }
 
 // Parameters representa los parámetros para crear un chirp
@@ -23,14 +34,17 @@ type ChirpCreationParams struct {
 
 // Request para crear usuario
 type CreateUserRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	Username    string `json:"username,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
 }
 
 // Request para actualizar usuario
 type UpdateUserRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email           string `json:"email"`
+	Password        string `json:"password"`
+	CurrentPassword string `json:"current_password"`
 }
 
 // Request para login
@@ -46,6 +60,7 @@ type CreateUserResponse struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Email       string    `json:"email"`
+	Username    string    `json:"username,omitempty"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
 }
 
@@ -55,6 +70,7 @@ type LoginResponse struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	Email        string    `json:"email"`
+	Username     string    `json:"username,omitempty"`
 	Token        string    `json:"token"`
 	RefreshToken string    `json:"refresh_token"`
 	IsChirpyRed  bool      `json:"is_chirpy_red"`
@@ -62,9 +78,219 @@ type LoginResponse struct {
 
 // Response con los datos actualizados del usuario
 type UpdateUserResponse struct {
+	ID           uuid.UUID `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Email        string    `json:"email"`
+	PendingEmail string    `json:"pending_email,omitempty"`
+	Username     string    `json:"username,omitempty"`
+	DisplayName  string    `json:"display_name,omitempty"`
+	Bio          string    `json:"bio,omitempty"`
+	AvatarURL    string    `json:"avatar_url,omitempty"`
+	IsChirpyRed  bool      `json:"is_chirpy_red"`
+}
+
+// Request para PATCH /api/users/me: solo se actualizan los campos presentes
+type PatchUserRequest struct {
+	Email           *string `json:"email"`
+	Password        *string `json:"password"`
+	CurrentPassword string  `json:"current_password"`
+	Username        *string `json:"username"`
+	DisplayName     *string `json:"display_name"`
+	Bio             *string `json:"bio"`
+	AvatarURL       *string `json:"avatar_url"`
+}
+
+// Request para confirmar un cambio de email
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// Usuario tal como lo ven los administradores
+type AdminUserResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Email          string     `json:"email"`
+	IsChirpyRed    bool       `json:"is_chirpy_red"`
+	Role           string     `json:"role"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	BannedAt       *time.Time `json:"banned_at,omitempty"`
+}
+
+// Request para cambiar el rol de un usuario
+type UpdateRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// Request para suspender a un usuario durante un número de horas
+type SuspendUserRequest struct {
+	Hours int `json:"hours"`
+}
+
+// Entrada del registro de auditoría de acciones administrativas
+type AuditLogEntry struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty"`
+	Action     string     `json:"action"`
+	TargetType string     `json:"target_type"`
+	TargetID   uuid.UUID  `json:"target_id"`
+	Details    string     `json:"details,omitempty"`
+}
+
+// Request para reportar un chirp o un usuario
+type ReportRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+// Request para resolver un reporte de la cola de moderación
+type ResolveReportRequest struct {
+	Action       string `json:"action"`
+	SuspendHours int    `json:"suspend_hours,omitempty"`
+}
+
+// Reporte tal como aparece en la cola de moderación
+type ReportResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ReporterID     uuid.UUID  `json:"reporter_id"`
+	ReportedUserID uuid.UUID  `json:"reported_user_id"`
+	ChirpID        *uuid.UUID `json:"chirp_id,omitempty"`
+	Reason         string     `json:"reason"`
+	Details        string     `json:"details,omitempty"`
+	Status         string     `json:"status"`
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolvedBy     *uuid.UUID `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Usuario bloqueado o silenciado por el usuario autenticado
+type UserRelationship struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Perfil público de un usuario (nunca incluye el email)
+type PublicProfile struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	AvatarURL   string    `json:"avatar_url"`
+}
+
+// Request para actualizar el perfil público
+type UpdateProfileRequest struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+// Request para eliminar la cuenta (requiere confirmar la contraseña)
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// Respuesta al programar la eliminación de una cuenta
+type DeleteAccountResponse struct {
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after"`
+}
+
+// AccountExport contiene todos los datos de un usuario (exportación GDPR)
+type AccountExport struct {
+	ExportedAt          time.Time           `json:"exported_at"`
+	Profile             ExportedProfile     `json:"profile"`
+	Chirps              []Chirp             `json:"chirps"`
+	Sessions            []ExportedSession   `json:"sessions"`
+	SubscriptionHistory []SubscriptionEvent `json:"subscription_history"`
+	Blocks              []UserRelationship  `json:"blocks"`
+	Mutes               []UserRelationship  `json:"mutes"`
+	Likes               []ExportedLike      `json:"likes"`
+	Following           []UserRelationship  `json:"following"`
+	Followers           []UserRelationship  `json:"followers"`
+}
+
+// Perfil completo del usuario, incluido su email
+type ExportedProfile struct {
 	ID          uuid.UUID `json:"id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Email       string    `json:"email"`
+	Username    string    `json:"username,omitempty"`
+	DisplayName string    `json:"display_name,omitempty"`
+	Bio         string    `json:"bio,omitempty"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	Role        string    `json:"role"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
 }
+
+// Like dado por el usuario
+type ExportedLike struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Sesión (refresh token) sin el valor del token
+type ExportedSession struct {
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Evento del historial de suscripción a Chirpy Red
+type SubscriptionEvent struct {
+	CreatedAt time.Time `json:"created_at"`
+	Event     string    `json:"event"`
+	Source    string    `json:"source"`
+}
+
+// Notificación del usuario autenticado. Las del mismo tipo se agrupan: Actor es el
+// último que actuó y ActorCount cuántos distintos hay en el grupo
+type Notification struct {
+	ID         uuid.UUID  `json:"id"`
+	Type       string     `json:"type"`
+	ChirpID    *uuid.UUID `json:"chirp_id,omitempty"`
+	Actor      *Author    `json:"actor,omitempty"`
+	ActorCount int        `json:"actor_count"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+}
+
+// Página de notificaciones con el total sin leer
+type NotificationList struct {
+	UnreadCount   int64          `json:"unread_count"`
+	Notifications []Notification `json:"notifications"`
+}
+
+// Datos del evento chirp.deleted de GET /api/stream/chirps y /api/ws
+type ChirpDeletedEvent struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Mensaje del cliente por el WebSocket de /api/ws
+type WSRequest struct {
+	Type  string `json:"type"` // subscribe, unsubscribe o ping
+	Topic string `json:"topic,omitempty"`
+	ID    string `json:"id,omitempty"` // opcional, se repite en la respuesta
+}
+
+// Mensaje del servidor por el WebSocket de /api/ws
+type WSMessage struct {
+	Type    string   `json:"type"` // subscribed, unsubscribed, pong, event o error
+	ID      string   `json:"id,omitempty"`
+	Topic   string   `json:"topic,omitempty"`
+	Topics  []string `json:"topics,omitempty"` // temas suscritos a los que pertenece el evento
+	Event   string   `json:"event,omitempty"`
+	EventID string   `json:"event_id,omitempty"`
+	Data    any      `json:"data,omitempty"`
+	Code    Code     `json:"code,omitempty"`
+	Detail  string   `json:"detail,omitempty"`
+}
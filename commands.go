@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/migrate"
+)
+
+// runCommand ejecuta un subcomando en lugar del servidor y devuelve el código de salida.
+// Por ahora el único subcomando es "migrate up|down|status|redo".
+func runCommand(cfg config.Config, args []string) int {
+	usage := "usage: chirpy [flags] migrate " + strings.Join(migrate.Commands, "|")
+	if args[0] != "migrate" || len(args) != 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	// Migrar solo necesita la base de datos, no el resto de secretos
+	if cfg.Database.URL == "" {
+		fmt.Fprintln(os.Stderr, "database.url (DB_URL) is required")
+		return 2
+	}
+
+	db, dialect, err := openDatabase(cfg.Database)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Could not connect to database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	provider, err := migrate.NewProvider(db, dialect)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Could not load migrations:", err)
+		return 1
+	}
+
+	if err := migrate.Run(context.Background(), provider, args[1], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+	return 0
+}
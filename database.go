@@ -0,0 +1,37 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/migrate"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/sqlitedb"
+)
+
+// openDatabase abre la base de datos de cfg.URL y devuelve también su dialecto de migraciones.
+// Las URL "sqlite://" usan SQLite (desarrollo local sin infraestructura); el resto, Postgres.
+func openDatabase(cfg config.DatabaseConfig) (*sql.DB, string, error) {
+	if sqlitedb.IsURL(cfg.URL) {
+		db, err := sqlitedb.Open(cfg.URL)
+		return db, migrate.DialectSQLite, err
+	}
+
+	db, err := sql.Open("postgres", cfg.URL)
+	if err != nil {
+		return nil, "", err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return db, migrate.DialectPostgres, nil
+}
+
+// newStore crea el store que corresponde al dialecto de db.
+func newStore(db *sql.DB, dialect string) service.Store {
+	if dialect == migrate.DialectSQLite {
+		return sqlitedb.NewStore(db)
+	}
+	return service.NewPostgresStore(db)
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+)
+
+// DeleteAccount programa la eliminación de la cuenta del usuario autenticado.
+// La cuenta queda desactivada de inmediato y se purga al terminar el periodo de gracia.
+func (h *Handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req api.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	// Re-confirma la contraseña, desactiva la cuenta y cierra todas las sesiones abiertas.
+	// Durante el periodo de gracia la cuenta puede recuperarse iniciando sesión.
+	deleted, err := h.svc.DeleteAccount(r.Context(), userID, req.Password)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusAccepted, api.DeleteAccountResponse{
+		DeletedAt:  deleted.DeletedAt.Time,
+		PurgeAfter: deleted.PurgeAfter.Time,
+	})
+}
+
+// ExportAccount devuelve un archivo JSON con todos los datos del usuario autenticado.
+func (h *Handler) ExportAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
+		return
+	}
+
+	chirps, err := h.db.ListChirpsByUser(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	sessions, err := h.db.ListRefreshTokensForUser(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	events, err := h.db.ListSubscriptionEvents(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	blocks, err := h.db.ListBlocks(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	mutes, err := h.db.ListMutes(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	likes, err := h.db.ListLikesByUser(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	following, err := h.db.ListFollowing(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	followers, err := h.db.ListFollowers(r.Context(), user.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	export := api.AccountExport{
+		ExportedAt: time.Now().UTC(),
+		Profile: api.ExportedProfile{
+			ID:          user.ID,
+			CreatedAt:   user.CreatedAt,
+			UpdatedAt:   user.UpdatedAt,
+			Email:       user.Email,
+			Username:    user.Username.String,
+			DisplayName: user.DisplayName,
+			Bio:         user.Bio,
+			AvatarURL:   user.AvatarUrl,
+			Role:        user.Role,
+			IsChirpyRed: user.IsChirpyRed,
+		},
+		Chirps:              []api.Chirp{},
+		Sessions:            []api.ExportedSession{},
+		SubscriptionHistory: []api.SubscriptionEvent{},
+		Blocks:              []api.UserRelationship{},
+		Mutes:               []api.UserRelationship{},
+		Likes:               []api.ExportedLike{},
+		Following:           followingResponse(following),
+		Followers:           followersResponse(followers),
+	}
+
+	for _, chirp := range chirps {
+		export.Chirps = append(export.Chirps, chirpResponse(chirp, nil))
+	}
+	for _, session := range sessions {
+		item := api.ExportedSession{
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+		}
+		if session.RevokedAt.Valid {
+			item.RevokedAt = &session.RevokedAt.Time
+		}
+		export.Sessions = append(export.Sessions, item)
+	}
+	for _, event := range events {
+		export.SubscriptionHistory = append(export.SubscriptionHistory, api.SubscriptionEvent{
+			CreatedAt: event.CreatedAt,
+			Event:     event.Event,
+			Source:    event.Source,
+		})
+	}
+	for _, block := range blocks {
+		export.Blocks = append(export.Blocks, api.UserRelationship{UserID: block.BlockedID, CreatedAt: block.CreatedAt})
+	}
+	for _, mute := range mutes {
+		export.Mutes = append(export.Mutes, api.UserRelationship{UserID: mute.MutedID, CreatedAt: mute.CreatedAt})
+	}
+	for _, like := range likes {
+		export.Likes = append(export.Likes, api.ExportedLike{ChirpID: like.ChirpID, CreatedAt: like.CreatedAt})
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chirpy-export-%s.json"`, user.ID))
+	api.RespondWithJSON(w, http.StatusOK, export)
+}
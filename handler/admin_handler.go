@@ -1,39 +1,360 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/idempotency"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/mail"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/ratelimit"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
 )
 
 type Handler struct {
-	db        *database.Queries
+	db        Store
+	svc       *service.Service
 	platform  string
 	jwtSecret string
 	polkaKey  string
+	mailer    mail.Sender
+
+	accessTokenTTL time.Duration
+	signupsEnabled bool
+
+	limiter     *ratelimit.Limiter // nil si el rate limiting está desactivado
+	rateLimits  rateLimitPolicies
+	idempotency *idempotency.Keys
+	events      *events.Broker
 }
 
-func NewHandler(db *database.Queries, platform string, jwtSecret string, polkaKey string) *Handler {
-	return &Handler{
+// NewHandler crea los handlers sobre db. Los emails salen por el Sender de cfg.Mail;
+// SetMailer permite reemplazarlo.
+func NewHandler(db Store, cfg config.Config) *Handler {
+	h := &Handler{
 		db:        db,
-		platform:  platform,
-		jwtSecret: jwtSecret,
-		polkaKey:  polkaKey,
+		svc:       service.New(db, cfg),
+		platform:  cfg.Platform,
+		jwtSecret: cfg.Auth.JWTSecret,
+		polkaKey:  cfg.Auth.PolkaKey,
+		mailer:    mail.New(cfg.Mail),
+
+		accessTokenTTL: cfg.Auth.AccessTokenTTL,
+		signupsEnabled: cfg.Features.Signups,
+		rateLimits:     newRateLimitPolicies(cfg.RateLimit),
 	}
+	h.idempotency = idempotency.New(db, h.idempotencyScope, cfg.Idempotency.KeyTTL)
+	h.events = events.NewBroker(streamHistory, streamBuffer)
+	h.svc.SetPublisher(h.events)
+	if cfg.Features.RateLimit {
+		h.limiter = ratelimit.NewLimiter(ratelimit.NewMemoryStore(), h.rateLimitKey)
+	}
+	return h
+}
+
+// SetMailer cambia cómo se envían los emails, por ejemplo para capturarlos en tests.
+func (h *Handler) SetMailer(m mail.Sender) {
+	h.mailer = m
 }
 
 func (h *Handler) ResetDatabase(w http.ResponseWriter, r *http.Request) {
 	if h.platform != "dev" {
-		api.RespondWithError(w, http.StatusForbidden, "Action not allowed in production", nil)
+		api.RespondWithError(w, r, http.StatusForbidden, api.CodeActionNotAllowed, nil)
 		return
 	}
 
 	err := h.db.Reset(r.Context())
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Could not reset database", err)
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
 		return
 	}
 
 	api.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Database reset successful"})
 }
+
+// ListUsers devuelve los usuarios paginados, con búsqueda opcional por email (?q=).
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireRole(w, r, RoleModerator); !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPagination, err)
+		return
+	}
+
+	users, err := h.db.ListUsers(r.Context(), database.ListUsersParams{
+		Query:     r.URL.Query().Get("q"),
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	response := []api.AdminUserResponse{}
+	for _, user := range users {
+		response = append(response, adminUserResponse(user))
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// UpdateUserRole cambia el rol de un usuario. Solo disponible para administradores.
+func (h *Handler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.requireRole(w, r, RoleAdmin)
+	if !ok {
+		return
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req api.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	user, err := h.svc.SetUserRole(r.Context(), admin.ID, targetID, req.Role)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, adminUserResponse(user))
+}
+
+// SuspendUser suspende a un usuario durante las horas indicadas.
+func (h *Handler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.requireRole(w, r, RoleModerator)
+	if !ok {
+		return
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req api.SuspendUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	user, err := h.svc.SuspendUser(r.Context(), moderator.ID, targetID, req.Hours)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, adminUserResponse(user))
+}
+
+// BanUser banea a un usuario de forma indefinida.
+func (h *Handler) BanUser(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.requireRole(w, r, RoleAdmin)
+	if !ok {
+		return
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.svc.BanUser(r.Context(), admin.ID, targetID)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, adminUserResponse(user))
+}
+
+// ReinstateUser levanta la suspensión o el baneo de un usuario.
+func (h *Handler) ReinstateUser(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.requireRole(w, r, RoleAdmin)
+	if !ok {
+		return
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.svc.ReinstateUser(r.Context(), admin.ID, targetID)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, adminUserResponse(user))
+}
+
+// GrantChirpyRed activa Chirpy Red manualmente para un usuario.
+func (h *Handler) GrantChirpyRed(w http.ResponseWriter, r *http.Request) {
+	h.setChirpyRed(w, r, true)
+}
+
+// RevokeChirpyRed desactiva Chirpy Red manualmente para un usuario.
+func (h *Handler) RevokeChirpyRed(w http.ResponseWriter, r *http.Request) {
+	h.setChirpyRed(w, r, false)
+}
+
+func (h *Handler) setChirpyRed(w http.ResponseWriter, r *http.Request, enabled bool) {
+	admin, ok := h.requireRole(w, r, RoleAdmin)
+	if !ok {
+		return
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.svc.SetChirpyRed(r.Context(), admin.ID, targetID, enabled)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, adminUserResponse(user))
+}
+
+// AdminDeleteChirp elimina cualquier chirp, sin importar el autor.
+func (h *Handler) AdminDeleteChirp(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.requireRole(w, r, RoleModerator)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidChirpID, err)
+		return
+	}
+
+	if err := h.svc.RemoveChirp(r.Context(), moderator.ID, chirpID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAuditLogs devuelve el registro de auditoría, del más reciente al más antiguo.
+func (h *Handler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireRole(w, r, RoleAdmin); !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPagination, err)
+		return
+	}
+
+	entries, err := h.db.ListAuditLogs(r.Context(), database.ListAuditLogsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	response := []api.AuditLogEntry{}
+	for _, entry := range entries {
+		item := api.AuditLogEntry{
+			ID:         entry.ID,
+			CreatedAt:  entry.CreatedAt,
+			Action:     entry.Action,
+			TargetType: entry.TargetType,
+			TargetID:   entry.TargetID,
+			Details:    entry.Details,
+		}
+		if entry.ActorID.Valid {
+			item.ActorID = &entry.ActorID.UUID
+		}
+		response = append(response, item)
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// Metrics expone las métricas de Prometheus a los administradores.
+// Solo se registra cuando no hay un listener de métricas separado (METRICS_ADDR).
+func (h *Handler) Metrics(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireRole(w, r, RoleAdmin); !ok {
+		return
+	}
+
+	metrics.Handler().ServeHTTP(w, r)
+}
+
+func adminUserResponse(user database.User) api.AdminUserResponse {
+	response := api.AdminUserResponse{
+		ID:          user.ID,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		Email:       user.Email,
+		IsChirpyRed: user.IsChirpyRed,
+		Role:        user.Role,
+	}
+	if user.SuspendedUntil.Valid {
+		response.SuspendedUntil = &user.SuspendedUntil.Time
+	}
+	if user.BannedAt.Valid {
+		response.BannedAt = &user.BannedAt.Time
+	}
+	return response
+}
+
+func parseUserIDParam(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidUserID, err)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// parsePagination lee ?limit= y ?offset= con valores por defecto razonables.
+func parsePagination(r *http.Request) (int32, int32, error) {
+	const defaultLimit, maxLimit = 50, 100
+
+	limit := int64(defaultLimit)
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || n <= 0 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		limit = min(n, maxLimit)
+	}
+
+	offset := int64(0)
+	if s := r.URL.Query().Get("offset"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+
+	return int32(limit), int32(offset), nil
+}
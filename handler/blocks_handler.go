@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// BlockUser bloquea a un usuario: no verá los chirps del bloqueador ni podrá responderle,
+// darle like ni seguirlo, y los follows entre ambos se deshacen.
+func (h *Handler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := h.relationshipTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.BlockUser(r.Context(), userID, targetID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnblockUser elimina un bloqueo existente.
+func (h *Handler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := h.relationshipTarget(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.db.UnblockUser(r.Context(), database.UnblockUserParams{
+		BlockerID: userID,
+		BlockedID: targetID,
+	})
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListBlocks devuelve los usuarios bloqueados por el usuario autenticado.
+func (h *Handler) ListBlocks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	blocks, err := h.db.ListBlocks(r.Context(), userID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	response := []api.UserRelationship{}
+	for _, block := range blocks {
+		response = append(response, api.UserRelationship{
+			UserID:    block.BlockedID,
+			CreatedAt: block.CreatedAt,
+		})
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// MuteUser silencia a un usuario: sus chirps dejan de aparecer en los listados del usuario autenticado.
+func (h *Handler) MuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := h.relationshipTarget(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.db.MuteUser(r.Context(), database.MuteUserParams{
+		MuterID: userID,
+		MutedID: targetID,
+	})
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnmuteUser elimina un silenciamiento existente.
+func (h *Handler) UnmuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := h.relationshipTarget(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.db.UnmuteUser(r.Context(), database.UnmuteUserParams{
+		MuterID: userID,
+		MutedID: targetID,
+	})
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMutes devuelve los usuarios silenciados por el usuario autenticado.
+func (h *Handler) ListMutes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	mutes, err := h.db.ListMutes(r.Context(), userID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	response := []api.UserRelationship{}
+	for _, mute := range mutes {
+		response = append(response, api.UserRelationship{
+			UserID:    mute.MutedID,
+			CreatedAt: mute.CreatedAt,
+		})
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// relationshipTarget autentica al usuario y valida el {userID} de la ruta.
+func (h *Handler) relationshipTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if targetID == userID {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeCannotTargetSelf, nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if _, err := h.db.GetUserByID(r.Context(), targetID); err != nil {
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, targetID, true
+}
+
+// isBlockedBy indica si blockerID bloqueó a userID. Ante un error se asume bloqueado.
+func (h *Handler) isBlockedBy(ctx context.Context, blockerID, userID uuid.UUID) bool {
+	if blockerID == userID {
+		return false
+	}
+	blocked, err := h.db.IsBlocked(ctx, database.IsBlockedParams{
+		BlockerID: blockerID,
+		BlockedID: userID,
+	})
+	return err != nil || blocked
+}
+
+// hiddenAuthors devuelve los usuarios que userID silenció o bloqueó y los que lo bloquearon.
+func (h *Handler) hiddenAuthors(r *http.Request, userID uuid.UUID) (map[uuid.UUID]struct{}, error) {
+	hidden := make(map[uuid.UUID]struct{})
+	mutes, err := h.db.ListMutes(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, mute := range mutes {
+		hidden[mute.MutedID] = struct{}{}
+	}
+	blocks, err := h.db.ListBlocks(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		hidden[block.BlockedID] = struct{}{}
+	}
+	blockers, err := h.db.ListBlockers(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blockers {
+		hidden[block.BlockerID] = struct{}{}
+	}
+	return hidden, nil
+}
+
+// chirpsForViewer devuelve los chirps visibles para el lector, sin los autores que silenció o bloqueó.
+func (h *Handler) chirpsForViewer(ctx context.Context, viewer database.User, hasViewer bool) ([]database.GetChirpsRow, error) {
+	if !hasViewer {
+		return h.db.GetChirps(ctx)
+	}
+
+	rows, err := h.db.GetChirpsForViewer(ctx, viewer.ID)
+	if err != nil {
+		return nil, err
+	}
+	chirps := make([]database.GetChirpsRow, 0, len(rows))
+	for _, row := range rows {
+		chirps = append(chirps, database.GetChirpsRow(row))
+	}
+	return chirps, nil
+}
@@ -5,122 +5,122 @@ import (
 	"errors"
 	"net/http"
 	"sort"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/amadrigalIstmo/Chirpy-project/api"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/middleware"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
 	"github.com/google/uuid"
 )
 
 // / CreateChirp maneja la creación de un chirp, validando la autenticación con JWT.
 func (h *Handler) CreateChirp(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		Body string `json:"body"`
+		Body      string     `json:"body"`
+		ReplyToID *uuid.UUID `json:"reply_to_id"`
 	}
 
 	// Obtener y validar el token JWT del header Authorization
 	token, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeMissingToken, err)
 		return
 	}
 
 	userID, err := auth.ValidateJWT(token, h.jwtSecret)
 	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidToken, err)
 		return
 	}
+	middleware.SetUserID(r.Context(), userID)
 
 	// Decodificar el cuerpo de la solicitud
 	decoder := json.NewDecoder(r.Body)
 	params := parameters{}
 	err = decoder.Decode(&params)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
 		return
 	}
 
-	// Validar y limpiar el chirp
-	cleaned, err := validateChirp(params.Body)
-	if err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, err.Error(), err)
-		return
+	var replyToID uuid.NullUUID
+	if params.ReplyToID != nil {
+		replyToID = uuid.NullUUID{UUID: *params.ReplyToID, Valid: true}
 	}
 
-	// Crear el chirp en la base de datos
-	chirp, err := h.db.CreateChirp(r.Context(), database.CreateChirpParams{
-		Body:   cleaned,
-		UserID: userID,
-	})
-	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Couldn't create chirp", err)
+	// Validar y limpiar el chirp y crearlo si el autor puede publicar: los usuarios
+	// restringidos no pueden y no se puede mencionar a quien te bloqueó
+	chirp, author, err := h.svc.CreateChirp(r.Context(), userID, params.Body, replyToID)
+	if errors.Is(err, service.ErrUserNotFound) {
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidToken, nil)
 		return
 	}
-
-	// Responder con el chirp creado
-	api.RespondWithJSON(w, http.StatusCreated, api.Chirp{
-		ID:        chirp.ID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
-		Body:      chirp.Body,
-		UserID:    chirp.UserID,
-	})
-}
-
-func (h *Handler) GetChirps(w http.ResponseWriter, r *http.Request) {
-	chirps, err := h.db.GetChirps(r.Context())
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Could not retrieve chirps", err)
+		respondWithServiceError(w, r, err)
 		return
 	}
 
-	var response []api.Chirp
-	for _, chirp := range chirps {
-		response = append(response, api.Chirp{
-			ID:        chirp.ID,
-			CreatedAt: chirp.CreatedAt,
-			UpdatedAt: chirp.UpdatedAt,
-			Body:      chirp.Body,
-			UserID:    chirp.UserID,
-		})
-	}
+	metrics.ChirpsCreated.Inc()
 
-	api.RespondWithJSON(w, http.StatusOK, response)
+	// Responder con el chirp creado
+	api.RespondWithJSON(w, http.StatusCreated, chirpResponse(chirp,
+		authorResponse(author.ID, author.Username, author.DisplayName, author.AvatarUrl)))
 }
 
 func (h *Handler) GetChirpByID(w http.ResponseWriter, r *http.Request) {
 	chirpIDStr := r.PathValue("chirpID")
 	if chirpIDStr == "" {
-		api.RespondWithError(w, http.StatusBadRequest, "Chirp ID is required", nil)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidChirpID, nil)
 		return
 	}
 
 	chirpID, err := uuid.Parse(chirpIDStr)
 	if err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "Invalid chirp ID format", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidChirpID, err)
 		return
 	}
 
+	// Chirp y autor se leen por separado para aprovechar el caché de lectura
 	chirp, err := h.db.GetChirp(r.Context(), chirpID)
 	if err != nil {
-		api.RespondWithError(w, http.StatusNotFound, "Chirp not found", err)
+		api.RespondWithDBError(w, r, api.CodeChirpNotFound, err)
+		return
+	}
+	author, err := h.db.GetUserByID(r.Context(), chirp.UserID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeChirpNotFound, err)
 		return
 	}
 
-	response := api.Chirp{
-		ID:        chirp.ID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
-		Body:      chirp.Body,
-		UserID:    chirp.UserID,
+	// Los chirps ocultos por moderación, de cuentas pendientes de eliminación o de autores
+	// que bloquearon al lector se tratan como inexistentes
+	viewer, hasViewer := h.optionalViewer(r)
+	if author.DeletedAt.Valid || !canViewChirp(chirp, viewer, hasViewer) ||
+		(hasViewer && h.isBlockedBy(r.Context(), chirp.UserID, viewer.ID)) {
+		api.RespondWithError(w, r, http.StatusNotFound, api.CodeChirpNotFound, nil)
+		return
+	}
+
+	// La respuesta incluye el perfil del autor: si cambia, también cambian ETag y Last-Modified
+	lastModified := chirp.UpdatedAt
+	if author.UpdatedAt.After(lastModified) {
+		lastModified = author.UpdatedAt
+	}
+	w.Header().Add("Vary", "Authorization")
+	if api.NotModified(w, r, chirpETag(chirp, author), lastModified) {
+		return
 	}
 
-	api.RespondWithJSON(w, http.StatusOK, response)
+	api.RespondWithJSON(w, http.StatusOK, chirpResponse(chirp,
+		authorResponse(author.ID, author.Username, author.DisplayName, author.AvatarUrl)))
 }
 
-// PolkaGetChirps maneja la obtención de chirps con filtro opcional por author_id.
 // PolkaGetChirps maneja la obtención de chirps con filtro opcional por author_id y ordenamiento por created_at.
+// Con following=true devuelve el timeline del usuario autenticado: sus chirps y los de quienes sigue.
 func (h *Handler) PolkaGetChirps(w http.ResponseWriter, r *http.Request) {
 	// Obtener el parámetro opcional "author_id"
 	authorIDString := r.URL.Query().Get("author_id")
@@ -131,17 +131,33 @@ func (h *Handler) PolkaGetChirps(w http.ResponseWriter, r *http.Request) {
 	if authorIDString != "" {
 		parsedID, err := uuid.Parse(authorIDString)
 		if err != nil {
-			api.RespondWithError(w, http.StatusBadRequest, "Invalid author ID", err)
+			api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidAuthorID, err)
 			return
 		}
 		authorID = parsedID
 		filterByAuthor = true
 	}
 
-	// Obtener todos los chirps de la base de datos
-	dbChirps, err := h.db.GetChirps(r.Context())
+	// Obtener los chirps visibles para quien hace la petición (sin autores silenciados o bloqueados)
+	viewer, hasViewer := h.optionalViewer(r)
+
+	var followed map[uuid.UUID]struct{}
+	if r.URL.Query().Get("following") == "true" {
+		if !hasViewer {
+			api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeMissingToken, nil)
+			return
+		}
+		var err error
+		followed, err = h.followedAuthors(r, viewer.ID)
+		if err != nil {
+			api.RespondWithDBError(w, r, api.CodeNotFound, err)
+			return
+		}
+	}
+
+	dbChirps, err := h.chirpsForViewer(r.Context(), viewer, hasViewer)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
 		return
 	}
 
@@ -151,20 +167,20 @@ func (h *Handler) PolkaGetChirps(w http.ResponseWriter, r *http.Request) {
 		sortDirection = "asc"
 	}
 
-	// Filtrar chirps si se proporcionó un author_id
+	// Filtrar chirps si se proporcionó un author_id y descartar los ocultos
 	chirps := []api.Chirp{}
-	for _, dbChirp := range dbChirps {
-		if filterByAuthor && dbChirp.UserID != authorID {
+	for _, row := range dbChirps {
+		if filterByAuthor && row.Chirp.UserID != authorID {
+			continue
+		}
+		if _, ok := followed[row.Chirp.UserID]; followed != nil && !ok {
+			continue
+		}
+		if !canViewChirp(row.Chirp, viewer, hasViewer) {
 			continue
 		}
 
-		chirps = append(chirps, api.Chirp{
-			ID:        dbChirp.ID,
-			CreatedAt: dbChirp.CreatedAt,
-			UpdatedAt: dbChirp.UpdatedAt,
-			UserID:    dbChirp.UserID,
-			Body:      dbChirp.Body,
-		})
+		chirps = append(chirps, chirpRowResponse(row))
 	}
 
 	// Ordenar los chirps según el parámetro "sort"
@@ -175,6 +191,19 @@ func (h *Handler) PolkaGetChirps(w http.ResponseWriter, r *http.Request) {
 		return chirps[i].CreatedAt.Before(chirps[j].CreatedAt)
 	})
 
+	// ETag débil sobre el listado completo: cambia si cambia cualquier chirp, su autor o el
+	// conjunto de chirps. Sin Last-Modified: borrar, ocultar o dejar de seguir quita chirps
+	// sin que cambie ninguna fecha, y If-Modified-Since daría un 304 con el listado viejo.
+	body, err := json.Marshal(chirps)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusInternalServerError, api.CodeInternalError, err)
+		return
+	}
+	w.Header().Add("Vary", "Authorization")
+	if api.NotModified(w, r, api.WeakETag(string(body)), time.Time{}) {
+		return
+	}
+
 	api.RespondWithJSON(w, http.StatusOK, chirps)
 }
 
@@ -182,46 +211,51 @@ func (h *Handler) DeleteChirp(w http.ResponseWriter, r *http.Request) {
 	// Extraer el `chirpID` de la URL
 	chirpIDStr := r.PathValue("chirpID")
 	if chirpIDStr == "" {
-		api.RespondWithError(w, http.StatusBadRequest, "Chirp ID is required", nil)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidChirpID, nil)
 		return
 	}
 
 	chirpID, err := uuid.Parse(chirpIDStr)
 	if err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "Invalid chirp ID format", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidChirpID, err)
 		return
 	}
 
 	// Extraer el `userID` desde el token JWT en los headers
 	token, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeMissingToken, err)
 		return
 	}
 
 	userID, err := auth.ValidateJWT(token, h.jwtSecret)
 	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidToken, err)
 		return
 	}
+	middleware.SetUserID(r.Context(), userID)
 
-	// Obtener el chirp desde la base de datos para verificar el dueño
-	chirp, err := h.db.GetChirp(r.Context(), chirpID)
-	if err != nil {
-		api.RespondWithError(w, http.StatusNotFound, "Chirp not found", err)
+	// El cliente debe indicar qué versión del chirp quiere borrar, para no borrar
+	// sin saberlo una versión que no vio
+	if r.Header.Get("If-Match") == "" {
+		api.RespondWithError(w, r, http.StatusPreconditionRequired, api.CodePreconditionRequired, nil)
 		return
 	}
-
-	// Verificar que el usuario autenticado sea el dueño del chirp
-	if chirp.UserID != userID {
-		api.RespondWithError(w, http.StatusForbidden, "You are not the owner of this chirp", nil)
+	// El ETag que vio el cliente incluye al autor, que es quien borra: se lee antes de la
+	// transacción porque solo él puede cambiar su perfil
+	author, err := h.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
 		return
 	}
+	unchanged := func(chirp database.Chirp) bool {
+		_, matches := api.IfMatch(r, chirpETag(chirp, author))
+		return matches
+	}
 
-	// Eliminar el chirp
-	err = h.db.DeleteChirp(r.Context(), chirpID)
-	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete chirp", err)
+	// Eliminar el chirp si el usuario autenticado es su dueño y no cambió
+	if err := h.svc.DeleteChirp(r.Context(), userID, chirpID, unchanged); err != nil {
+		respondWithServiceError(w, r, err)
 		return
 	}
 
@@ -229,29 +263,31 @@ func (h *Handler) DeleteChirp(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func validateChirp(body string) (string, error) {
-	const maxChirpLength = 140
-	if len(body) > maxChirpLength {
-		return "", errors.New("Chirp is too long")
-	}
+// chirpETag es el ETag fuerte de un chirp, derivado de su ID y del updated_at del chirp y
+// de su autor, cuyo perfil forma parte de la respuesta.
+func chirpETag(chirp database.Chirp, author database.User) string {
+	return api.StrongETag(chirp.ID.String(),
+		strconv.FormatInt(chirp.UpdatedAt.UnixMicro(), 10),
+		strconv.FormatInt(author.UpdatedAt.UnixMicro(), 10))
+}
 
-	badWords := map[string]struct{}{
-		"kerfuffle": {},
-		"sharbert":  {},
-		"fornax":    {},
+func chirpResponse(chirp database.Chirp, author *api.Author) api.Chirp {
+	response := api.Chirp{
+		ID:        chirp.ID,
+		CreatedAt: chirp.CreatedAt,
+		UpdatedAt: chirp.UpdatedAt,
+		UserID:    chirp.UserID,
+		Body:      chirp.Body,
+		Hidden:    chirp.HiddenAt.Valid,
+		Author:    author,
 	}
-
-	cleaned := getCleanedBody(body, badWords)
-	return cleaned, nil
+	if chirp.ReplyToID.Valid {
+		response.ReplyToID = &chirp.ReplyToID.UUID
+	}
+	return response
 }
 
-func getCleanedBody(body string, badWords map[string]struct{}) string {
-	words := strings.Split(body, " ")
-	for i, word := range words {
-		loweredWord := strings.ToLower(word)
-		if _, ok := badWords[loweredWord]; ok {
-			words[i] = "****"
-		}
-	}
-	return strings.Join(words, " ")
+// chirpRowResponse convierte un chirp con los datos del autor obtenidos con JOIN.
+func chirpRowResponse(row database.GetChirpsRow) api.Chirp {
+	return chirpResponse(row.Chirp, authorResponse(row.Chirp.UserID, row.Username, row.DisplayName, row.AvatarUrl))
 }
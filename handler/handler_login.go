@@ -2,59 +2,38 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/amadrigalIstmo/Chirpy-project/api"
-	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
-	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/middleware"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
 )
 
 // Login permite a un usuario autenticarse y recibir un JWT
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req api.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
 		return
 	}
 
-	// Buscar usuario por email
-	user, err := h.db.GetUserByEmail(r.Context(), req.Email)
-	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Incorrect email or password", nil)
-		return
+	// Verificar credenciales, reactivar la cuenta si estaba pendiente de eliminación y
+	// guardar el refresh token, todo en una transacción
+	session, err := h.svc.Login(r.Context(), req.Email, req.Password)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
 	}
-
-	// Comparar contraseñas
-	if err := auth.CheckPasswordHash(req.Password, user.HashedPassword); err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Incorrect email or password", nil)
-		return
-	}
-
-	// Generar Access Token (JWT) - válido por 1 hora
-	accessToken, err := auth.MakeJWT(user.ID, h.jwtSecret, time.Hour)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Could not generate access token", err)
+		respondWithServiceError(w, r, err)
 		return
 	}
+	user := session.User
+	middleware.SetUserID(r.Context(), user.ID)
 
-	// Generar Refresh Token - válido por 60 días
-	refreshToken, err := auth.MakeRefreshToken()
-	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Could not generate refresh token", err)
-		return
-	}
-
-	// Guardar Refresh Token en la base de datos
-	_, err = h.db.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().UTC().Add(time.Hour * 24 * 60), // 60 días
-	})
-	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Could not save refresh token", err)
-		return
-	}
+	metrics.Logins.WithLabelValues(metrics.LoginSucceeded).Inc()
+	metrics.RefreshTokens.WithLabelValues(metrics.RefreshTokenIssued).Inc()
 
 	// Responder con usuario, access token y refresh token
 	response := api.LoginResponse{
@@ -62,9 +41,10 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:    user.CreatedAt,
 		UpdatedAt:    user.UpdatedAt,
 		Email:        user.Email,
+		Username:     user.Username.String,
 		IsChirpyRed:  user.IsChirpyRed,
-		Token:        accessToken,
-		RefreshToken: refreshToken,
+		Token:        session.AccessToken,
+		RefreshToken: session.RefreshToken,
 	}
 
 	api.RespondWithJSON(w, http.StatusOK, response)
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/amadrigalIstmo/Chirpy-project/api"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/middleware"
 )
 
 // Handler para refrescar el token de acceso
@@ -16,26 +18,38 @@ func (h *Handler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
 
 	refreshToken, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "No se encontró el token", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeMissingToken, err)
 		return
 	}
 
 	user, err := h.db.GetUserFromRefreshToken(r.Context(), refreshToken)
+	if database.IsNotFound(err) {
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidRefreshToken, nil)
+		return
+	}
 	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "No se pudo obtener el usuario para el refresh token", err)
+		api.RespondWithDBError(w, r, api.CodeInvalidRefreshToken, err)
+		return
+	}
+	middleware.SetUserID(r.Context(), user.ID)
+
+	if code := accountRestriction(user); code != "" {
+		api.RespondWithError(w, r, http.StatusForbidden, code, nil)
 		return
 	}
 
 	accessToken, err := auth.MakeJWT(
 		user.ID,
 		h.jwtSecret,
-		time.Hour, // Token de acceso válido por 1 hora
+		h.accessTokenTTL,
 	)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "No se pudo generar un nuevo token de acceso", err)
+		api.RespondWithError(w, r, http.StatusInternalServerError, api.CodeInternalError, err)
 		return
 	}
 
+	metrics.RefreshTokens.WithLabelValues(metrics.RefreshTokenUsed).Inc()
+
 	api.RespondWithJSON(w, http.StatusOK, response{
 		Token: accessToken,
 	})
@@ -45,15 +59,16 @@ func (h *Handler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) RevokeTokenHandler(w http.ResponseWriter, r *http.Request) {
 	refreshToken, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "No se encontró el token", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeMissingToken, err)
 		return
 	}
 
 	_, err = h.db.RevokeRefreshToken(r.Context(), refreshToken)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "No se pudo revocar la sesión", err)
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
 		return
 	}
+	metrics.RefreshTokens.WithLabelValues(metrics.RefreshTokenRevoked).Inc()
 
 	w.WriteHeader(http.StatusNoContent) // 204 No Content (sin cuerpo)
 }
@@ -0,0 +1,1028 @@
+package handler_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/handler"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/cache"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/memstore"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/migrate"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/sqlitedb"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	_ "github.com/lib/pq"
+)
+
+var (
+	_ handler.Store = (*memstore.Store)(nil)
+	_ handler.Store = (*sqlitedb.Store)(nil)
+	_ handler.Store = (*service.PostgresStore)(nil)
+	_ handler.Store = (*cache.Store)(nil)
+)
+
+// backends devuelve los Stores contra los que corre la suite: siempre memoria (con y sin
+// caché) y SQLite, y Postgres si TEST_DB_URL apunta a una base de datos desechable (se
+// migra y se vacía).
+func backends(t *testing.T) map[string]func(t *testing.T) handler.Store {
+	stores := map[string]func(t *testing.T) handler.Store{
+		"memory": func(t *testing.T) handler.Store { return memstore.New() },
+		"cached": func(t *testing.T) handler.Store {
+			return cache.NewStore(memstore.New(), cache.NewLRU(100), time.Minute)
+		},
+		"sqlite": openSQLite,
+	}
+
+	dbURL := os.Getenv("TEST_DB_URL")
+	if dbURL == "" {
+		return stores
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	provider, err := migrate.NewProvider(db, migrate.DialectPostgres)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+	stores["postgres"] = func(t *testing.T) handler.Store {
+		store := service.NewPostgresStore(db)
+		if err := store.Reset(context.Background()); err != nil {
+			t.Fatalf("reset test database: %v", err)
+		}
+		return store
+	}
+	return stores
+}
+
+// openSQLite crea una base de datos SQLite nueva y migrada en un directorio temporal.
+func openSQLite(t *testing.T) handler.Store {
+	db, err := sqlitedb.Open("sqlite://" + filepath.Join(t.TempDir(), "chirpy.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	provider, err := migrate.NewProvider(db, migrate.DialectSQLite)
+	if err != nil {
+		t.Fatalf("load sqlite migrations: %v", err)
+	}
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	return sqlitedb.NewStore(db)
+}
+
+// forEachBackend ejecuta test una vez por backend, con un servidor nuevo y vacío.
+func forEachBackend(t *testing.T, test func(t *testing.T, c *client)) {
+	for name, open := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			test(t, newClient(t, open(t)))
+		})
+	}
+}
+
+type client struct {
+	server *httptest.Server
+	store  handler.Store
+	mail   *mailbox
+}
+
+// mailbox guarda los emails que envía el servidor en lugar de mandarlos.
+type mailbox struct {
+	mu   sync.Mutex
+	sent map[string][]string
+}
+
+func (m *mailbox) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent[to] = append(m.sent[to], body)
+	return nil
+}
+
+// last devuelve el último email enviado a to.
+func (m *mailbox) last(t *testing.T, to string) string {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.sent[to]) == 0 {
+		t.Fatalf("no email sent to %s", to)
+	}
+	return m.sent[to][len(m.sent[to])-1]
+}
+
+func newClient(t *testing.T, store handler.Store) *client {
+	return newClientWithConfig(t, store, func(cfg *config.Config) {
+		// Todas las peticiones de los tests salen de la misma IP
+		cfg.Features.RateLimit = false
+	})
+}
+
+// newClientWithConfig es como newClient pero permite ajustar la configuración del handler.
+func newClientWithConfig(t *testing.T, store handler.Store, configure func(cfg *config.Config)) *client {
+	cfg := config.Default()
+	cfg.Platform = "dev"
+	cfg.Auth.JWTSecret = "test-secret"
+	cfg.Auth.PolkaKey = "test-polka-key"
+	configure(&cfg)
+
+	mail := &mailbox{sent: make(map[string][]string)}
+	h := handler.NewHandler(store, cfg)
+	h.SetMailer(mail)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	server := httptest.NewServer(api.WithRequestID(mux))
+	t.Cleanup(server.Close)
+
+	return &client{server: server, store: store, mail: mail}
+}
+
+// do envía la petición y decodifica la respuesta JSON en out, si no es nil.
+func (c *client) do(t *testing.T, method, path, token string, body any, out any) int {
+	t.Helper()
+	resp := c.send(t, method, path, token, nil, body, out)
+	return resp.StatusCode
+}
+
+// send es como do pero añade headers a la petición y devuelve la respuesta completa.
+func (c *client) send(t *testing.T, method, path, token string, headers map[string]string, body any, out any) *http.Response {
+	t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req, err := http.NewRequest(method, c.server.URL+path, &reader)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decode response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+// expectProblem comprueba el status y el código de un problem+json.
+func (c *client) expectProblem(t *testing.T, method, path, token string, body any, status int, code api.Code) {
+	t.Helper()
+
+	var problem api.Problem
+	got := c.do(t, method, path, token, body, &problem)
+	if got != status || problem.Code != code {
+		t.Fatalf("%s %s = %d %q, want %d %q", method, path, got, problem.Code, status, code)
+	}
+}
+
+// signup crea un usuario e inicia sesión con él.
+func (c *client) signup(t *testing.T, email string) api.LoginResponse {
+	t.Helper()
+
+	creds := api.CreateUserRequest{Email: email, Password: "hunter2"}
+	if status := c.do(t, "POST", "/api/users", "", creds, nil); status != http.StatusCreated {
+		t.Fatalf("create user %s = %d, want %d", email, status, http.StatusCreated)
+	}
+	var login api.LoginResponse
+	if status := c.do(t, "POST", "/api/login", "", creds, &login); status != http.StatusOK {
+		t.Fatalf("login %s = %d, want %d", email, status, http.StatusOK)
+	}
+	return login
+}
+
+func (c *client) chirp(t *testing.T, token, body string) api.Chirp {
+	t.Helper()
+
+	var chirp api.Chirp
+	if status := c.do(t, "POST", "/api/chirps", token, map[string]string{"body": body}, &chirp); status != http.StatusCreated {
+		t.Fatalf("create chirp = %d, want %d", status, http.StatusCreated)
+	}
+	return chirp
+}
+
+func TestCreateUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		var user api.CreateUserResponse
+		status := c.do(t, "POST", "/api/users", "", api.CreateUserRequest{Email: "saul@bettercall.com", Password: "hunter2"}, &user)
+		if status != http.StatusCreated || user.Email != "saul@bettercall.com" || user.ID == uuid.Nil {
+			t.Fatalf("create user = %d %+v", status, user)
+		}
+
+		c.expectProblem(t, "POST", "/api/users", "", api.CreateUserRequest{Email: "saul@bettercall.com", Password: "other"},
+			http.StatusConflict, api.CodeAccountExists)
+		// Los emails no distinguen mayúsculas
+		c.expectProblem(t, "POST", "/api/users", "", api.CreateUserRequest{Email: " Saul@BetterCall.com", Password: "other"},
+			http.StatusConflict, api.CodeAccountExists)
+		c.expectProblem(t, "POST", "/api/users", "", api.CreateUserRequest{Email: "kim@wexler.com"},
+			http.StatusBadRequest, api.CodeValidationFailed)
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+
+		// PUT sigue las reglas de PATCH: sin la contraseña actual no cambia nada
+		c.expectProblem(t, "PUT", "/api/users", walt.Token, api.UpdateUserRequest{Email: "heisenberg@breakingbad.com", Password: "blue"},
+			http.StatusUnauthorized, api.CodeIncorrectPassword)
+
+		var user api.UpdateUserResponse
+		update := api.UpdateUserRequest{Email: "Heisenberg@BreakingBad.com", Password: "blue", CurrentPassword: "hunter2"}
+		if status := c.do(t, "PUT", "/api/users", walt.Token, update, &user); status != http.StatusOK {
+			t.Fatalf("update user = %d, want %d", status, http.StatusOK)
+		}
+		if user.Email != "walt@breakingbad.com" || user.PendingEmail != "heisenberg@breakingbad.com" {
+			t.Fatalf("update user = %+v, want email pending verification", user)
+		}
+
+		// La contraseña sí cambia de inmediato y el login ignora mayúsculas
+		login := api.LoginRequest{Email: "WALT@breakingbad.com", Password: "blue"}
+		if status := c.do(t, "POST", "/api/login", "", login, nil); status != http.StatusOK {
+			t.Fatalf("login with new password = %d, want %d", status, http.StatusOK)
+		}
+
+		// El token del email enviado confirma el cambio
+		fields := strings.Fields(c.mail.last(t, "heisenberg@breakingbad.com"))
+		verify := api.VerifyEmailRequest{Token: fields[len(fields)-1]}
+		var verified api.UpdateUserResponse
+		if status := c.do(t, "POST", "/api/users/verify-email", "", verify, &verified); status != http.StatusOK || verified.Email != "heisenberg@breakingbad.com" {
+			t.Fatalf("verify email = %d %+v, want the new email", status, verified)
+		}
+		login = api.LoginRequest{Email: "heisenberg@breakingbad.com", Password: "blue"}
+		if status := c.do(t, "POST", "/api/login", "", login, nil); status != http.StatusOK {
+			t.Fatalf("login with verified email = %d, want %d", status, http.StatusOK)
+		}
+
+		// Cambiar solo las mayúsculas del email no es un cambio
+		email := "HEISENBERG@breakingbad.com"
+		patch := api.PatchUserRequest{Email: &email}
+		var patched api.UpdateUserResponse
+		if status := c.do(t, "PATCH", "/api/users/me", walt.Token, patch, &patched); status != http.StatusOK || patched.PendingEmail != "" {
+			t.Fatalf("patch email case = %d %+v, want no pending email", status, patched)
+		}
+	})
+}
+
+func TestLoginAndRefreshTokens(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		login := c.signup(t, "walt@breakingbad.com")
+
+		c.expectProblem(t, "POST", "/api/login", "", api.LoginRequest{Email: "walt@breakingbad.com", Password: "wrong"},
+			http.StatusUnauthorized, api.CodeInvalidCredentials)
+
+		var refreshed struct {
+			Token string `json:"token"`
+		}
+		if status := c.do(t, "POST", "/api/refresh", login.RefreshToken, nil, &refreshed); status != http.StatusOK || refreshed.Token == "" {
+			t.Fatalf("refresh = %d %+v", status, refreshed)
+		}
+
+		if status := c.do(t, "POST", "/api/revoke", login.RefreshToken, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("revoke = %d, want %d", status, http.StatusNoContent)
+		}
+		c.expectProblem(t, "POST", "/api/refresh", login.RefreshToken, nil, http.StatusUnauthorized, api.CodeInvalidRefreshToken)
+
+		// Un refresh token expirado se rechaza igual que uno revocado
+		_, err := c.store.CreateRefreshToken(context.Background(), database.CreateRefreshTokenParams{
+			Token:     "expired-token",
+			UserID:    login.ID,
+			ExpiresAt: time.Now().UTC().Add(-time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("create expired token: %v", err)
+		}
+		c.expectProblem(t, "POST", "/api/refresh", "expired-token", nil, http.StatusUnauthorized, api.CodeInvalidRefreshToken)
+	})
+}
+
+func TestChirps(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		jesse := c.signup(t, "jesse@breakingbad.com")
+
+		first := c.chirp(t, walt.Token, "I am the one who knocks")
+		c.chirp(t, jesse.Token, "Yeah science")
+		c.chirp(t, walt.Token, "Say my name")
+
+		c.expectProblem(t, "POST", "/api/chirps", "", map[string]string{"body": "anonymous"},
+			http.StatusUnauthorized, api.CodeMissingToken)
+
+		var chirps []api.Chirp
+		if status := c.do(t, "GET", "/api/chirps?author_id="+walt.ID.String(), "", nil, &chirps); status != http.StatusOK {
+			t.Fatalf("list chirps = %d", status)
+		}
+		if len(chirps) != 2 || chirps[0].ID != first.ID {
+			t.Fatalf("list chirps by author = %+v, want 2 chirps starting with %s", chirps, first.ID)
+		}
+
+		path := "/api/chirps/" + first.ID.String()
+		var got api.Chirp
+		if status := c.do(t, "GET", path, "", nil, &got); status != http.StatusOK || got.Body != first.Body {
+			t.Fatalf("get chirp = %d %+v", status, got)
+		}
+
+		ifMatch := map[string]string{"If-Match": "*"}
+		if resp := c.send(t, "DELETE", path, jesse.Token, ifMatch, nil, nil); resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("delete someone else's chirp = %d, want %d", resp.StatusCode, http.StatusForbidden)
+		}
+		if resp := c.send(t, "DELETE", path, walt.Token, ifMatch, nil, nil); resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("delete chirp = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+		c.expectProblem(t, "GET", path, "", nil, http.StatusNotFound, api.CodeChirpNotFound)
+	})
+}
+
+func TestBlockHidesChirps(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		hank := c.signup(t, "hank@dea.gov")
+		c.chirp(t, walt.Token, "I am the danger")
+
+		if status := c.do(t, "POST", "/api/users/"+walt.ID.String()+"/block", hank.Token, nil, nil); status >= 300 {
+			t.Fatalf("block = %d", status)
+		}
+
+		// El bloqueo oculta los chirps en ambos sentidos
+		for _, token := range []string{hank.Token, walt.Token} {
+			var chirps []api.Chirp
+			c.do(t, "GET", "/api/chirps?author_id="+walt.ID.String(), token, nil, &chirps)
+			if token == hank.Token && len(chirps) != 0 {
+				t.Errorf("blocker sees %d chirps from blocked user, want 0", len(chirps))
+			}
+			if token == walt.Token && len(chirps) != 1 {
+				t.Errorf("author sees %d of their own chirps, want 1", len(chirps))
+			}
+		}
+	})
+}
+
+func TestResetCascades(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		chirp := c.chirp(t, walt.Token, "Say my name")
+
+		if status := c.do(t, "POST", "/admin/reset", "", nil, nil); status != http.StatusOK {
+			t.Fatalf("reset = %d, want %d", status, http.StatusOK)
+		}
+
+		// Borrar el usuario borra en cascada sus chirps y refresh tokens
+		c.expectProblem(t, "GET", "/api/chirps/"+chirp.ID.String(), "", nil, http.StatusNotFound, api.CodeChirpNotFound)
+		c.expectProblem(t, "POST", "/api/refresh", walt.RefreshToken, nil, http.StatusUnauthorized, api.CodeInvalidRefreshToken)
+
+		// El email vuelve a estar disponible
+		c.signup(t, "walt@breakingbad.com")
+	})
+}
+
+func TestListUsersSearchIsLiteral(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		ctx := context.Background()
+		for _, email := range []string{"walt@breakingbad.com", "jesse_pinkman@breakingbad.com", "saul%goodman@breakingbad.com"} {
+			if _, err := c.store.CreateUser(ctx, database.CreateUserParams{Email: email}); err != nil {
+				t.Fatalf("create %s: %v", email, err)
+			}
+		}
+
+		// %, _ y \ se buscan tal cual, no como comodines
+		for query, want := range map[string]int{"": 3, "BREAKINGBAD": 3, "_": 1, "%": 1, "s_ul": 0, "w%t": 0, `\`: 0} {
+			users, err := c.store.ListUsers(ctx, database.ListUsersParams{Query: query, RowLimit: 10})
+			if err != nil {
+				t.Fatalf("ListUsers(%q) error = %v", query, err)
+			}
+			if len(users) != want {
+				t.Errorf("ListUsers(%q) = %d users, want %d", query, len(users), want)
+			}
+		}
+	})
+}
+
+func TestConditionalRequests(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		chirp := c.chirp(t, walt.Token, "Say my name")
+		path := "/api/chirps/" + chirp.ID.String()
+
+		resp := c.send(t, "GET", path, "", nil, nil, nil)
+		etag := resp.Header.Get("ETag")
+		if resp.StatusCode != http.StatusOK || etag == "" || strings.HasPrefix(etag, "W/") || resp.Header.Get("Last-Modified") == "" {
+			t.Fatalf("get chirp = %d, ETag %q, Last-Modified %q", resp.StatusCode, etag, resp.Header.Get("Last-Modified"))
+		}
+		if resp := c.send(t, "GET", path, "", map[string]string{"If-None-Match": etag}, nil, nil); resp.StatusCode != http.StatusNotModified {
+			t.Errorf("get chirp with If-None-Match = %d, want %d", resp.StatusCode, http.StatusNotModified)
+		}
+
+		// El listado lleva un ETag débil que cambia al publicar un chirp nuevo, y no lleva
+		// Last-Modified: quitar un chirp del listado no cambia ninguna fecha
+		resp = c.send(t, "GET", "/api/chirps", "", nil, nil, nil)
+		listETag := resp.Header.Get("ETag")
+		if !strings.HasPrefix(listETag, "W/") || resp.Header.Get("Last-Modified") != "" {
+			t.Fatalf("list chirps ETag %q, Last-Modified %q, want only a weak ETag", listETag, resp.Header.Get("Last-Modified"))
+		}
+		since := map[string]string{"If-Modified-Since": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}
+		if resp := c.send(t, "GET", "/api/chirps", "", since, nil, nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("list chirps with If-Modified-Since = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if resp := c.send(t, "GET", "/api/chirps", "", map[string]string{"If-None-Match": listETag}, nil, nil); resp.StatusCode != http.StatusNotModified {
+			t.Errorf("list chirps with the current ETag = %d, want %d", resp.StatusCode, http.StatusNotModified)
+		}
+		c.chirp(t, walt.Token, "I am the one who knocks")
+		if resp := c.send(t, "GET", "/api/chirps", "", map[string]string{"If-None-Match": listETag}, nil, nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("list chirps with a stale ETag = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+
+		// Cambiar el perfil del autor cambia la respuesta y por tanto el ETag
+		displayName := "Heisenberg"
+		if status := c.do(t, "PATCH", "/api/users/me", walt.Token, api.PatchUserRequest{DisplayName: &displayName}, nil); status != http.StatusOK {
+			t.Fatalf("patch profile = %d, want %d", status, http.StatusOK)
+		}
+		resp = c.send(t, "GET", path, "", map[string]string{"If-None-Match": etag}, nil, nil)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+			t.Fatalf("get chirp after profile change = %d, ETag %q, want a new ETag", resp.StatusCode, resp.Header.Get("ETag"))
+		}
+		staleETag, etag := etag, resp.Header.Get("ETag")
+
+		// Borrar exige If-Match con el ETag actual
+		c.expectProblem(t, "DELETE", path, walt.Token, nil, http.StatusPreconditionRequired, api.CodePreconditionRequired)
+		var problem api.Problem
+		resp = c.send(t, "DELETE", path, walt.Token, map[string]string{"If-Match": staleETag}, nil, &problem)
+		if resp.StatusCode != http.StatusPreconditionFailed || problem.Code != api.CodePreconditionFailed {
+			t.Errorf("delete with a stale ETag = %d %q, want %d", resp.StatusCode, problem.Code, http.StatusPreconditionFailed)
+		}
+		if resp := c.send(t, "DELETE", path, walt.Token, map[string]string{"If-Match": etag}, nil, nil); resp.StatusCode != http.StatusNoContent {
+			t.Errorf("delete with the current ETag = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+
+		// Los clientes que aún no guardan el ETag pueden borrar con If-Match: *
+		other := c.chirp(t, walt.Token, "Tread lightly")
+		if resp := c.send(t, "DELETE", "/api/chirps/"+other.ID.String(), walt.Token, map[string]string{"If-Match": "*"}, nil, nil); resp.StatusCode != http.StatusNoContent {
+			t.Errorf("delete with If-Match: * = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	c := newClientWithConfig(t, memstore.New(), func(cfg *config.Config) {
+		cfg.RateLimit.Signup = 2
+		cfg.RateLimit.Chirps = 1
+		cfg.RateLimit.RedMultiplier = 3
+	})
+
+	// signup y login se limitan por IP
+	walt := c.signup(t, "walt@breakingbad.com")
+	creds := api.CreateUserRequest{Email: "jesse@breakingbad.com", Password: "hunter2"}
+	resp := c.send(t, "POST", "/api/users", "", nil, creds, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("second signup = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if got := resp.Header.Get("RateLimit-Remaining"); got != "0" || resp.Header.Get("RateLimit-Limit") != "2" {
+		t.Errorf("RateLimit-Limit %q, RateLimit-Remaining %q, want 2 and 0", resp.Header.Get("RateLimit-Limit"), got)
+	}
+	var problem api.Problem
+	creds.Email = "skyler@breakingbad.com"
+	resp = c.send(t, "POST", "/api/users", "", nil, creds, &problem)
+	if resp.StatusCode != http.StatusTooManyRequests || problem.Code != api.CodeRateLimited || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("third signup = %d %q, Retry-After %q", resp.StatusCode, problem.Code, resp.Header.Get("Retry-After"))
+	}
+
+	// Los chirps se limitan por usuario, y con Chirpy Red la cuota se multiplica
+	c.chirp(t, walt.Token, "Say my name")
+	c.expectProblem(t, "POST", "/api/chirps", walt.Token, map[string]string{"body": "Again"}, http.StatusTooManyRequests, api.CodeRateLimited)
+
+	var jesse api.LoginResponse
+	creds.Email = "jesse@breakingbad.com"
+	if status := c.do(t, "POST", "/api/login", "", creds, &jesse); status != http.StatusOK {
+		t.Fatalf("login = %d, want %d", status, http.StatusOK)
+	}
+	if _, err := c.store.UpgradeToChirpyRed(context.Background(), jesse.ID); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		c.chirp(t, jesse.Token, "Yeah, science!")
+	}
+	c.expectProblem(t, "POST", "/api/chirps", jesse.Token, map[string]string{"body": "Again"}, http.StatusTooManyRequests, api.CodeRateLimited)
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		// Claves únicas: en Postgres la tabla no se vacía entre tests
+		key := map[string]string{"Idempotency-Key": uuid.NewString()}
+		body := map[string]string{"body": "Say my name"}
+
+		var first, retry api.Chirp
+		if resp := c.send(t, "POST", "/api/chirps", walt.Token, key, body, &first); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("create chirp = %d, want %d", resp.StatusCode, http.StatusCreated)
+		}
+		resp := c.send(t, "POST", "/api/chirps", walt.Token, key, body, &retry)
+		if resp.StatusCode != http.StatusCreated || retry.ID != first.ID || resp.Header.Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("retry = %d, chirp %s, replayed %q, want the stored response for %s",
+				resp.StatusCode, retry.ID, resp.Header.Get("Idempotent-Replayed"), first.ID)
+		}
+		var chirps []api.Chirp
+		c.do(t, "GET", "/api/chirps", "", nil, &chirps)
+		if len(chirps) != 1 {
+			t.Errorf("got %d chirps after a retry, want 1", len(chirps))
+		}
+
+		// La misma clave con otro cuerpo se rechaza
+		var problem api.Problem
+		resp = c.send(t, "POST", "/api/chirps", walt.Token, key, map[string]string{"body": "Another"}, &problem)
+		if resp.StatusCode != http.StatusUnprocessableEntity || problem.Code != api.CodeIdempotencyKeyReused {
+			t.Errorf("reused key = %d %q, want %d %q", resp.StatusCode, problem.Code, http.StatusUnprocessableEntity, api.CodeIdempotencyKeyReused)
+		}
+
+		// Las claves de cada usuario son independientes
+		jesse := c.signup(t, "jesse@breakingbad.com")
+		var other api.Chirp
+		if resp := c.send(t, "POST", "/api/chirps", jesse.Token, key, body, &other); resp.StatusCode != http.StatusCreated || other.ID == first.ID {
+			t.Errorf("same key for another user = %d, chirp %s", resp.StatusCode, other.ID)
+		}
+
+		// Un registro repetido devuelve la respuesta original en lugar de un conflicto
+		signupKey := map[string]string{"Idempotency-Key": uuid.NewString()}
+		creds := api.CreateUserRequest{Email: "skyler@breakingbad.com", Password: "hunter2"}
+		for i := 0; i < 2; i++ {
+			if resp := c.send(t, "POST", "/api/users", "", signupKey, creds, nil); resp.StatusCode != http.StatusCreated {
+				t.Fatalf("signup attempt %d = %d, want %d", i+1, resp.StatusCode, http.StatusCreated)
+			}
+		}
+
+		// El login no guarda su respuesta: cada intento abre una sesión nueva
+		var logins [2]api.LoginResponse
+		for i := range logins {
+			resp := c.send(t, "POST", "/api/login", "", signupKey, creds, &logins[i])
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Idempotent-Replayed") != "" {
+				t.Fatalf("login attempt %d = %d, replayed %q", i+1, resp.StatusCode, resp.Header.Get("Idempotent-Replayed"))
+			}
+		}
+		if logins[0].RefreshToken == logins[1].RefreshToken {
+			t.Errorf("login retries share refresh token %q, want a new session each time", logins[0].RefreshToken)
+		}
+
+		long := map[string]string{"Idempotency-Key": strings.Repeat("k", 256)}
+		resp = c.send(t, "POST", "/api/chirps", walt.Token, long, body, &problem)
+		if resp.StatusCode != http.StatusBadRequest || problem.Code != api.CodeInvalidIdempotencyKey {
+			t.Errorf("long key = %d %q, want %d %q", resp.StatusCode, problem.Code, http.StatusBadRequest, api.CodeInvalidIdempotencyKey)
+		}
+	})
+}
+
+// sseEvent es un evento leído de un stream SSE.
+type sseEvent struct {
+	id, event, data string
+}
+
+// openStream abre GET path como stream SSE y devuelve una función que lee el siguiente evento.
+func (c *client) openStream(t *testing.T, path string, headers map[string]string) func() sseEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := c.server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("GET %s = %d %q, want an event stream", path, resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	return func() sseEvent {
+		t.Helper()
+		var e sseEvent
+		for lines.Scan() {
+			line := lines.Text()
+			switch {
+			case line == "" && e.event != "":
+				return e
+			case strings.HasPrefix(line, "id: "):
+				e.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				e.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				e.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+		t.Fatalf("stream ended: %v", lines.Err())
+		return e
+	}
+}
+
+func TestStreamChirps(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		jesse := c.signup(t, "jesse@breakingbad.com")
+
+		next := c.openStream(t, "/api/stream/chirps?author_id="+walt.ID.String(), nil)
+		c.chirp(t, jesse.Token, "Yeah, science!")
+		created := c.chirp(t, walt.Token, "Say my name")
+
+		// El chirp de Jesse no pasa el filtro por autor
+		e := next()
+		var chirp api.Chirp
+		if err := json.Unmarshal([]byte(e.data), &chirp); err != nil {
+			t.Fatalf("decode event data %q: %v", e.data, err)
+		}
+		if e.event != "chirp.created" || chirp.ID != created.ID || chirp.Body != "Say my name" {
+			t.Fatalf("got %s %s, want chirp.created for %s", e.event, e.data, created.ID)
+		}
+
+		path := "/api/chirps/" + created.ID.String()
+		if resp := c.send(t, "DELETE", path, walt.Token, map[string]string{"If-Match": "*"}, nil, nil); resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("delete chirp = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+		deleted := next()
+		if deleted.event != "chirp.deleted" || !strings.Contains(deleted.data, created.ID.String()) {
+			t.Fatalf("got %s %s, want chirp.deleted for %s", deleted.event, deleted.data, created.ID)
+		}
+
+		// Al reconectarse con Last-Event-ID se recibe lo que vino después
+		resumed := c.openStream(t, "/api/stream/chirps", map[string]string{"Last-Event-ID": e.id})
+		if got := resumed(); got.id != deleted.id {
+			t.Errorf("resumed stream starts at %s %s, want %s", got.event, got.id, deleted.id)
+		}
+
+		// Con un JWT se omiten los autores bloqueados, igual que en el listado
+		hank := c.signup(t, "hank@dea.gov")
+		if status := c.do(t, "POST", "/api/users/"+walt.ID.String()+"/block", hank.Token, nil, nil); status >= 300 {
+			t.Fatalf("block = %d", status)
+		}
+		filtered := c.openStream(t, "/api/stream/chirps", map[string]string{"Authorization": "Bearer " + hank.Token})
+		c.chirp(t, walt.Token, "I am the one who knocks")
+		visible := c.chirp(t, jesse.Token, "Yo, Mr. White")
+		if got := filtered(); got.event != "chirp.created" || !strings.Contains(got.data, visible.ID.String()) {
+			t.Errorf("blocker got %s %s, want only %s", got.event, got.data, visible.ID)
+		}
+
+		// Walt tampoco recibe los chirps de Hank, que lo bloqueó, y con following=true
+		// solo recibe los de quienes sigue
+		marie := c.signup(t, "marie@dea.gov")
+		if status := c.do(t, "POST", "/api/users/"+jesse.ID.String()+"/follow", walt.Token, nil, nil); status >= 300 {
+			t.Fatalf("follow = %d", status)
+		}
+		blocked := c.openStream(t, "/api/stream/chirps", map[string]string{"Authorization": "Bearer " + walt.Token})
+		timeline := c.openStream(t, "/api/stream/chirps?following=true", map[string]string{"Authorization": "Bearer " + walt.Token})
+		c.chirp(t, hank.Token, "My name is ASAC Schrader")
+		fromMarie := c.chirp(t, marie.Token, "They're minerals, Hank")
+		fromJesse := c.chirp(t, jesse.Token, "Magnets, yo!")
+		if got := blocked(); got.event != "chirp.created" || !strings.Contains(got.data, fromMarie.ID.String()) {
+			t.Errorf("blocked user got %s %s, want %s", got.event, got.data, fromMarie.ID)
+		}
+		if got := timeline(); got.event != "chirp.created" || !strings.Contains(got.data, fromJesse.ID.String()) {
+			t.Errorf("timeline got %s %s, want %s", got.event, got.data, fromJesse.ID)
+		}
+
+		c.expectProblem(t, "GET", "/api/stream/chirps?author_id=nope", "", nil, http.StatusBadRequest, api.CodeInvalidAuthorID)
+		c.expectProblem(t, "GET", "/api/stream/chirps?following=true", "", nil, http.StatusUnauthorized, api.CodeMissingToken)
+	})
+}
+
+// dialWS abre /api/ws con el token en ?access_token=, como lo haría un navegador.
+func (c *client) dialWS(t *testing.T, token string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(c.server.URL, "http") + "/api/ws?access_token=" + token
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial /api/ws: %v (response %v)", err, resp)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// wsRequest envía req y devuelve la siguiente respuesta del servidor.
+func wsRequest(t *testing.T, conn *websocket.Conn, req api.WSRequest) api.WSMessage {
+	t.Helper()
+	if err := conn.WriteJSON(req); err != nil {
+		t.Fatalf("write %+v: %v", req, err)
+	}
+	return readWS(t, conn)
+}
+
+func readWS(t *testing.T, conn *websocket.Conn) api.WSMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg api.WSMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	return msg
+}
+
+func TestWebSocket(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		jesse := c.signup(t, "jesse@breakingbad.com")
+		hank := c.signup(t, "hank@dea.gov")
+
+		url := "ws" + strings.TrimPrefix(c.server.URL, "http") + "/api/ws"
+		if _, resp, err := websocket.DefaultDialer.Dial(url, nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("dial without token = %v, %v, want 401", resp, err)
+		}
+
+		// Hank bloqueó a Jesse, así que sus chirps no le llegan aunque esté suscrito. Los
+		// bloqueos se leen al conectarse
+		if status := c.do(t, "POST", "/api/users/"+jesse.ID.String()+"/block", hank.Token, nil, nil); status >= 300 {
+			t.Fatalf("block = %d", status)
+		}
+		conn := c.dialWS(t, jesse.Token)
+		if msg := wsRequest(t, conn, api.WSRequest{Type: "ping", ID: "1"}); msg.Type != "pong" || msg.ID != "1" {
+			t.Fatalf("ping = %+v, want pong", msg)
+		}
+		if msg := wsRequest(t, conn, api.WSRequest{Type: "subscribe", Topic: "everything"}); msg.Type != "error" || msg.Code != api.CodeInvalidTopic {
+			t.Fatalf("subscribe to unknown topic = %+v, want %s", msg, api.CodeInvalidTopic)
+		}
+		if msg := wsRequest(t, conn, api.WSRequest{Type: "shout"}); msg.Code != api.CodeInvalidMessageType {
+			t.Fatalf("unknown message type = %+v, want %s", msg, api.CodeInvalidMessageType)
+		}
+		for _, topic := range []string{"hashtag:#Science", "user:" + walt.ID.String(), "user:" + hank.ID.String()} {
+			if msg := wsRequest(t, conn, api.WSRequest{Type: "subscribe", Topic: topic}); msg.Type != "subscribed" {
+				t.Fatalf("subscribe to %s = %+v", topic, msg)
+			}
+		}
+
+		c.chirp(t, hank.Token, "Minerals, Marie")
+		c.chirp(t, hank.Token, "unrelated")
+		science := c.chirp(t, walt.Token, "Yeah, #science!")
+
+		msg := readWS(t, conn)
+		data, _ := json.Marshal(msg.Data)
+		var chirp api.Chirp
+		json.Unmarshal(data, &chirp)
+		if msg.Type != "event" || msg.Event != "chirp.created" || chirp.ID != science.ID {
+			t.Fatalf("got %+v, want chirp.created for %s", msg, science.ID)
+		}
+		if got := strings.Join(msg.Topics, ","); got != "user:"+walt.ID.String()+",hashtag:science" {
+			t.Errorf("event topics = %q", got)
+		}
+
+		if msg := wsRequest(t, conn, api.WSRequest{Type: "unsubscribe", Topic: "user:" + walt.ID.String()}); msg.Type != "unsubscribed" {
+			t.Fatalf("unsubscribe = %+v", msg)
+		}
+		if resp := c.send(t, "DELETE", "/api/chirps/"+science.ID.String(), walt.Token, map[string]string{"If-Match": "*"}, nil, nil); resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("delete chirp = %d", resp.StatusCode)
+		}
+		if msg := readWS(t, conn); msg.Event != "chirp.deleted" || strings.Join(msg.Topics, ",") != "hashtag:science" {
+			t.Fatalf("got %+v, want chirp.deleted for hashtag:science", msg)
+		}
+	})
+}
+
+func TestNotifications(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		jesse := c.signup(t, "jesse@breakingbad.com")
+		skyler := c.signup(t, "skyler@breakingbad.com")
+		profile := api.UpdateProfileRequest{Username: "heisenberg", DisplayName: "Heisenberg"}
+		if status := c.do(t, "PUT", "/api/users/me/profile", walt.Token, profile, nil); status != http.StatusOK {
+			t.Fatalf("update profile = %d", status)
+		}
+
+		conn := c.dialWS(t, walt.Token)
+		if msg := wsRequest(t, conn, api.WSRequest{Type: "subscribe", Topic: "notifications"}); msg.Type != "subscribed" {
+			t.Fatalf("subscribe = %+v", msg)
+		}
+
+		mention := c.chirp(t, jesse.Token, "@heisenberg say my name")
+		msg := readWS(t, conn)
+		if msg.Event != "notification.created" || strings.Join(msg.Topics, ",") != "notifications" {
+			t.Fatalf("got %+v, want notification.created", msg)
+		}
+		c.chirp(t, skyler.Token, "@Heisenberg dinner is ready")
+		c.chirp(t, jesse.Token, "@heisenberg yo")
+
+		var list api.NotificationList
+		if status := c.do(t, "GET", "/api/notifications", walt.Token, nil, &list); status != http.StatusOK {
+			t.Fatalf("list notifications = %d", status)
+		}
+		if list.UnreadCount != 1 || len(list.Notifications) != 1 {
+			t.Fatalf("got %+v, want one collapsed notification", list)
+		}
+		n := list.Notifications[0]
+		if n.Type != "mention" || n.ActorCount != 2 || n.Actor == nil || n.Actor.ID != jesse.ID || n.ChirpID == nil || *n.ChirpID == mention.ID {
+			t.Errorf("notification = %+v, want latest mention by %s from 2 actors", n, jesse.ID)
+		}
+		if status := c.do(t, "GET", "/api/notifications", jesse.Token, nil, &list); status != http.StatusOK || len(list.Notifications) != 0 {
+			t.Errorf("other user's notifications = %d %+v, want none", status, list)
+		}
+
+		c.expectProblem(t, "POST", "/api/notifications/nope/read", walt.Token, nil, http.StatusBadRequest, api.CodeInvalidNotificationID)
+		c.expectProblem(t, "POST", "/api/notifications/"+n.ID.String()+"/read", jesse.Token, nil, http.StatusNotFound, api.CodeNotificationNotFound)
+		if status := c.do(t, "POST", "/api/notifications/"+n.ID.String()+"/read", walt.Token, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("mark read = %d", status)
+		}
+		c.chirp(t, skyler.Token, "@heisenberg again")
+		if status := c.do(t, "GET", "/api/notifications?unread=true", walt.Token, nil, &list); status != http.StatusOK {
+			t.Fatalf("list unread = %d", status)
+		}
+		if list.UnreadCount != 1 || len(list.Notifications) != 1 || list.Notifications[0].ActorCount != 1 {
+			t.Fatalf("got %+v, want a new notification after reading", list)
+		}
+		if status := c.do(t, "POST", "/api/notifications/read", walt.Token, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("mark all read = %d", status)
+		}
+		if c.do(t, "GET", "/api/notifications", walt.Token, nil, &list); list.UnreadCount != 0 || len(list.Notifications) != 2 || list.Notifications[0].ReadAt == nil {
+			t.Fatalf("got %+v, want two read notifications", list)
+		}
+
+		var prefs map[string]bool
+		if status := c.do(t, "GET", "/api/notifications/preferences", walt.Token, nil, &prefs); status != http.StatusOK || !prefs["mention"] {
+			t.Fatalf("preferences = %d %v, want mentions enabled", status, prefs)
+		}
+		c.expectProblem(t, "PUT", "/api/notifications/preferences", walt.Token, map[string]bool{"likes": false}, http.StatusBadRequest, api.CodeInvalidNotificationType)
+		if status := c.do(t, "PUT", "/api/notifications/preferences", walt.Token, map[string]bool{"mention": false}, &prefs); status != http.StatusOK || prefs["mention"] {
+			t.Fatalf("disable mentions = %d %v", status, prefs)
+		}
+		c.chirp(t, jesse.Token, "@heisenberg are you there?")
+		if c.do(t, "GET", "/api/notifications", walt.Token, nil, &list); list.UnreadCount != 0 {
+			t.Fatalf("got %+v, want no notifications with mentions disabled", list)
+		}
+	})
+}
+
+func TestRepliesLikesAndFollows(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		jesse := c.signup(t, "jesse@breakingbad.com")
+		skyler := c.signup(t, "skyler@breakingbad.com")
+		chirp := c.chirp(t, walt.Token, "Say my name")
+
+		var reply api.Chirp
+		body := map[string]any{"body": "Heisenberg", "reply_to_id": chirp.ID}
+		if status := c.do(t, "POST", "/api/chirps", jesse.Token, body, &reply); status != http.StatusCreated {
+			t.Fatalf("reply = %d", status)
+		}
+		if reply.ReplyToID == nil || *reply.ReplyToID != chirp.ID {
+			t.Errorf("reply_to_id = %v, want %s", reply.ReplyToID, chirp.ID)
+		}
+		orphan := map[string]any{"body": "hello?", "reply_to_id": uuid.New()}
+		c.expectProblem(t, "POST", "/api/chirps", jesse.Token, orphan, http.StatusNotFound, api.CodeChirpNotFound)
+
+		for _, token := range []string{jesse.Token, skyler.Token, jesse.Token} {
+			if status := c.do(t, "POST", "/api/chirps/"+chirp.ID.String()+"/like", token, nil, nil); status != http.StatusNoContent {
+				t.Fatalf("like = %d", status)
+			}
+		}
+		c.expectProblem(t, "POST", "/api/chirps/"+uuid.NewString()+"/like", jesse.Token, nil, http.StatusNotFound, api.CodeChirpNotFound)
+		if status := c.do(t, "DELETE", "/api/chirps/"+chirp.ID.String()+"/like", skyler.Token, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("unlike = %d", status)
+		}
+
+		if status := c.do(t, "POST", "/api/users/"+walt.ID.String()+"/follow", jesse.Token, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("follow = %d", status)
+		}
+		c.expectProblem(t, "POST", "/api/users/"+jesse.ID.String()+"/follow", jesse.Token, nil, http.StatusBadRequest, api.CodeCannotTargetSelf)
+		c.expectProblem(t, "POST", "/api/users/"+uuid.NewString()+"/follow", jesse.Token, nil, http.StatusNotFound, api.CodeUserNotFound)
+		var following, followers []api.UserRelationship
+		if c.do(t, "GET", "/api/users/me/following", jesse.Token, nil, &following); len(following) != 1 || following[0].UserID != walt.ID {
+			t.Errorf("following = %+v, want walt", following)
+		}
+		if c.do(t, "GET", "/api/users/me/followers", walt.Token, nil, &followers); len(followers) != 1 || followers[0].UserID != jesse.ID {
+			t.Errorf("followers = %+v, want jesse", followers)
+		}
+
+		var list api.NotificationList
+		if status := c.do(t, "GET", "/api/notifications", walt.Token, nil, &list); status != http.StatusOK {
+			t.Fatalf("list notifications = %d", status)
+		}
+		counts := map[string]int{}
+		for _, n := range list.Notifications {
+			counts[n.Type] = n.ActorCount
+		}
+		if len(list.Notifications) != 3 || counts["reply"] != 1 || counts["like"] != 2 || counts["follow"] != 1 {
+			t.Fatalf("got %+v, want one reply, one like from 2 actors and one follow", list.Notifications)
+		}
+
+		var export api.AccountExport
+		if status := c.do(t, "GET", "/api/users/me/export", jesse.Token, nil, &export); status != http.StatusOK {
+			t.Fatalf("export = %d", status)
+		}
+		if len(export.Likes) != 1 || export.Likes[0].ChirpID != chirp.ID || len(export.Following) != 1 || len(export.Followers) != 0 {
+			t.Errorf("export likes = %+v, following = %+v, followers = %+v", export.Likes, export.Following, export.Followers)
+		}
+
+		if status := c.do(t, "DELETE", "/api/users/"+walt.ID.String()+"/follow", jesse.Token, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("unfollow = %d", status)
+		}
+		if c.do(t, "GET", "/api/users/me/following", jesse.Token, nil, &following); len(following) != 0 {
+			t.Errorf("following after unfollow = %+v, want none", following)
+		}
+	})
+}
+
+func TestFollowingTimelineAndBlocks(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, c *client) {
+		walt := c.signup(t, "walt@breakingbad.com")
+		jesse := c.signup(t, "jesse@breakingbad.com")
+		skyler := c.signup(t, "skyler@breakingbad.com")
+		saul := c.signup(t, "saul@bettercallsaul.com")
+		chirp := c.chirp(t, walt.Token, "Say my name")
+		c.chirp(t, skyler.Token, "Dinner is ready")
+		c.chirp(t, saul.Token, "Better call Saul")
+		c.chirp(t, jesse.Token, "Yeah, science")
+
+		for _, follow := range []struct{ token, userID string }{
+			{jesse.Token, walt.ID.String()},
+			{jesse.Token, skyler.ID.String()},
+			{walt.Token, jesse.ID.String()},
+		} {
+			if status := c.do(t, "POST", "/api/users/"+follow.userID+"/follow", follow.token, nil, nil); status != http.StatusNoContent {
+				t.Fatalf("follow = %d", status)
+			}
+		}
+
+		timeline := func() map[uuid.UUID]bool {
+			t.Helper()
+			var chirps []api.Chirp
+			if status := c.do(t, "GET", "/api/chirps?following=true", jesse.Token, nil, &chirps); status != http.StatusOK {
+				t.Fatalf("timeline = %d", status)
+			}
+			authors := map[uuid.UUID]bool{}
+			for _, chirp := range chirps {
+				authors[chirp.UserID] = true
+			}
+			return authors
+		}
+		if authors := timeline(); len(authors) != 3 || !authors[walt.ID] || !authors[skyler.ID] || !authors[jesse.ID] {
+			t.Errorf("timeline authors = %v, want jesse and the users they follow", authors)
+		}
+		c.expectProblem(t, "GET", "/api/chirps?following=true", "", nil, http.StatusUnauthorized, api.CodeMissingToken)
+
+		// Silenciar saca al usuario del timeline aunque se le siga
+		if status := c.do(t, "POST", "/api/users/"+skyler.ID.String()+"/mute", jesse.Token, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("mute = %d", status)
+		}
+		if authors := timeline(); authors[skyler.ID] {
+			t.Errorf("timeline authors = %v, want no muted users", authors)
+		}
+
+		// Bloquear deshace los follows en ambos sentidos e impide interactuar con el bloqueador
+		if status := c.do(t, "POST", "/api/users/"+jesse.ID.String()+"/block", walt.Token, nil, nil); status != http.StatusNoContent {
+			t.Fatalf("block = %d", status)
+		}
+		var following []api.UserRelationship
+		for _, token := range []string{walt.Token, jesse.Token} {
+			c.do(t, "GET", "/api/users/me/following", token, nil, &following)
+			for _, follow := range following {
+				if follow.UserID == walt.ID || follow.UserID == jesse.ID {
+					t.Errorf("following = %+v, want no follows between blocker and blocked", following)
+				}
+			}
+		}
+		if authors := timeline(); authors[walt.ID] {
+			t.Errorf("timeline authors = %v, want no blocking users", authors)
+		}
+
+		reply := map[string]any{"body": "Mr. White?", "reply_to_id": chirp.ID}
+		c.expectProblem(t, "POST", "/api/chirps", jesse.Token, reply, http.StatusForbidden, api.CodeBlockedByUser)
+		c.expectProblem(t, "POST", "/api/chirps/"+chirp.ID.String()+"/like", jesse.Token, nil, http.StatusForbidden, api.CodeBlockedByUser)
+		c.expectProblem(t, "POST", "/api/users/"+walt.ID.String()+"/follow", jesse.Token, nil, http.StatusForbidden, api.CodeBlockedByUser)
+	})
+}
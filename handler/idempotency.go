@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
+)
+
+// idempotent permite reintentar fn con el header Idempotency-Key sin repetir su efecto.
+// El login no lo usa: su respuesta lleva tokens que no deben quedar guardados, y repetirlo
+// solo abre otra sesión.
+func (h *Handler) idempotent(fn http.HandlerFunc) http.HandlerFunc {
+	return h.idempotency.Handler(fn).ServeHTTP
+}
+
+// idempotencyScope separa las claves de cada usuario. Las peticiones sin JWT válido
+// (el registro) comparten el ámbito anónimo: ahí la huella del cuerpo, que incluye
+// las credenciales, es la que impide que otro cliente reciba la respuesta guardada.
+func (h *Handler) idempotencyScope(r *http.Request) string {
+	if token, err := auth.GetBearerToken(r.Header); err == nil {
+		if userID, err := auth.ValidateJWT(token, h.jwtSecret); err == nil {
+			return "user:" + userID.String()
+		}
+	}
+	return "anonymous"
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// ListNotifications devuelve las notificaciones del usuario autenticado, de la más
+// reciente a la más antigua, y cuántas tiene sin leer. Con ?unread=true solo devuelve
+// las no leídas.
+func (h *Handler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPagination, err)
+		return
+	}
+
+	page, err := h.svc.ListNotifications(r.Context(), userID, r.URL.Query().Get("unread") == "true", limit, offset)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	response := api.NotificationList{UnreadCount: page.UnreadCount, Notifications: []api.Notification{}}
+	for _, row := range page.Notifications {
+		var actor *api.Author
+		if row.Notification.ActorID.Valid {
+			actor = authorResponse(row.Notification.ActorID.UUID, row.Username, row.DisplayName.String, row.AvatarUrl.String)
+		}
+		response.Notifications = append(response.Notifications, notificationResponse(row.Notification, actor))
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// MarkNotificationRead marca como leída una notificación del usuario autenticado.
+func (h *Handler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	notificationID, err := uuid.Parse(r.PathValue("notificationID"))
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidNotificationID, err)
+		return
+	}
+
+	if err := h.svc.MarkNotificationRead(r.Context(), userID, notificationID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllNotificationsRead marca como leídas todas las notificaciones del usuario autenticado.
+func (h *Handler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.MarkAllNotificationsRead(r.Context(), userID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetNotificationPreferences devuelve qué tipos de notificación tiene activados el
+// usuario autenticado.
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.svc.NotificationPreferences(r.Context(), userID)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, prefs)
+}
+
+// UpdateNotificationPreferences activa o desactiva tipos de notificación. El cuerpo es
+// un objeto tipo → activado; los tipos que no aparecen no cambian.
+func (h *Handler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var changes map[string]bool
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	prefs, err := h.svc.SetNotificationPreferences(r.Context(), userID, changes)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, prefs)
+}
+
+func notificationResponse(n database.Notification, actor *api.Author) api.Notification {
+	response := api.Notification{
+		ID:         n.ID,
+		Type:       n.Type,
+		Actor:      actor,
+		ActorCount: int(n.ActorCount),
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+	if n.ChirpID.Valid {
+		response.ChirpID = &n.ChirpID.UUID
+	}
+	if n.ReadAt.Valid {
+		response.ReadAt = &n.ReadAt.Time
+	}
+	return response
+}
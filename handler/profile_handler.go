@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
+// Nombres que chocan con rutas existentes (por ejemplo /api/users/me)
+var reservedUsernames = map[string]struct{}{
+	"me":    {},
+	"admin": {},
+}
+
+const (
+	maxDisplayNameLength = 50
+	maxBioLength         = 160
+	maxAvatarURLLength   = 2048
+)
+
+// GetUserProfile devuelve el perfil público de un usuario por su username.
+func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	if !usernamePattern.MatchString(username) {
+		api.RespondWithError(w, r, http.StatusNotFound, api.CodeUserNotFound, nil)
+		return
+	}
+
+	user, err := h.db.GetUserByUsername(r.Context(), username)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, publicProfile(user))
+}
+
+// UpdateProfile reemplaza el perfil público del usuario autenticado.
+func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req api.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	var fieldErrs []api.FieldError
+	if fieldErr := validateUsername(req.Username); fieldErr != nil {
+		fieldErrs = append(fieldErrs, *fieldErr)
+	}
+	fieldErrs = append(fieldErrs, validateProfileFields(req.DisplayName, req.Bio, req.AvatarURL)...)
+	if len(fieldErrs) > 0 {
+		api.RespondWithValidationErrors(w, r, fieldErrs...)
+		return
+	}
+
+	user, err := h.db.UpdateUserProfile(r.Context(), database.UpdateUserProfileParams{
+		ID:          userID,
+		Username:    sql.NullString{String: req.Username, Valid: true},
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		Bio:         strings.TrimSpace(req.Bio),
+		AvatarUrl:   req.AvatarURL,
+	})
+	if database.IsConflict(err) {
+		api.RespondWithError(w, r, http.StatusConflict, api.CodeUsernameTaken, nil)
+		return
+	}
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, publicProfile(user))
+}
+
+// validateUsername comprueba el formato del username y que no esté reservado.
+func validateUsername(username string) *api.FieldError {
+	if !usernamePattern.MatchString(username) {
+		return &api.FieldError{Field: "username", Code: api.CodeUsernameInvalid}
+	}
+	if _, reserved := reservedUsernames[strings.ToLower(username)]; reserved {
+		return &api.FieldError{Field: "username", Code: api.CodeUsernameReserved}
+	}
+	return nil
+}
+
+// validateProfileFields devuelve un error por cada campo del perfil que no es válido.
+func validateProfileFields(displayName, bio, avatarURL string) []api.FieldError {
+	var fieldErrs []api.FieldError
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+		fieldErrs = append(fieldErrs, api.FieldError{Field: "display_name", Code: api.CodeDisplayNameTooLong})
+	}
+	if utf8.RuneCountInString(bio) > maxBioLength {
+		fieldErrs = append(fieldErrs, api.FieldError{Field: "bio", Code: api.CodeBioTooLong})
+	}
+	if avatarURL != "" {
+		if len(avatarURL) > maxAvatarURLLength {
+			fieldErrs = append(fieldErrs, api.FieldError{Field: "avatar_url", Code: api.CodeAvatarURLTooLong})
+		} else if u, err := url.Parse(avatarURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			fieldErrs = append(fieldErrs, api.FieldError{Field: "avatar_url", Code: api.CodeAvatarURLInvalid})
+		}
+	}
+	return fieldErrs
+}
+
+func publicProfile(user database.User) api.PublicProfile {
+	return api.PublicProfile{
+		ID:          user.ID,
+		CreatedAt:   user.CreatedAt,
+		Username:    user.Username.String,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		AvatarURL:   user.AvatarUrl,
+	}
+}
+
+func authorResponse(userID uuid.UUID, username sql.NullString, displayName, avatarURL string) *api.Author {
+	return &api.Author{
+		ID:          userID,
+		Username:    username.String,
+		DisplayName: displayName,
+		AvatarURL:   avatarURL,
+	}
+}
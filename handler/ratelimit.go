@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/ratelimit"
+	"github.com/google/uuid"
+)
+
+// rateLimitPolicies son las políticas de rate limiting de las rutas.
+type rateLimitPolicies struct {
+	signup   ratelimit.Policy
+	login    ratelimit.Policy
+	chirps   ratelimit.Policy
+	webhooks ratelimit.Policy
+	standard ratelimit.Policy
+}
+
+func newRateLimitPolicies(cfg config.RateLimitConfig) rateLimitPolicies {
+	policy := func(name string, perMinute int) ratelimit.Policy {
+		return ratelimit.Policy{
+			Name:    name,
+			Limit:   ratelimit.PerMinute(perMinute),
+			Premium: ratelimit.PerMinute(perMinute * cfg.RedMultiplier),
+		}
+	}
+	return rateLimitPolicies{
+		signup:   policy("signup", cfg.Signup),
+		login:    policy("login", cfg.Login),
+		chirps:   policy("chirps", cfg.Chirps),
+		webhooks: policy("webhooks", cfg.Webhooks),
+		standard: policy("default", cfg.Default),
+	}
+}
+
+// SetRateLimitStore cambia dónde se guardan los buckets del rate limiting. Por defecto
+// se usa un ratelimit.MemoryStore, que no se comparte entre réplicas.
+// Debe llamarse antes de RegisterRoutes; no hace nada si features.rate_limit está desactivado.
+func (h *Handler) SetRateLimitStore(store ratelimit.Store) {
+	if h.limiter != nil {
+		h.limiter = ratelimit.NewLimiter(store, h.rateLimitKey)
+	}
+}
+
+// rateLimited aplica policy a fn si el rate limiting está activado.
+func (h *Handler) rateLimited(policy ratelimit.Policy, fn http.HandlerFunc) http.Handler {
+	if h.limiter == nil {
+		return fn
+	}
+	return h.limiter.Handler(policy, fn)
+}
+
+// rateLimitKey identifica al cliente: el usuario del JWT si es válido, con su estado de
+// Chirpy Red, o si no la IP. Se usa RemoteAddr, así que detrás de un proxy este debe
+// reescribirla con la IP real del cliente.
+func (h *Handler) rateLimitKey(r *http.Request) (string, bool) {
+	if token, err := auth.GetBearerToken(r.Header); err == nil {
+		if userID, err := auth.ValidateJWT(token, h.jwtSecret); err == nil {
+			return "user:" + userID.String(), h.isChirpyRed(r, userID)
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return "ip:" + ip, false
+}
+
+// isChirpyRed indica si el usuario tiene Chirpy Red; si no se puede leer, se trata como si no.
+func (h *Handler) isChirpyRed(r *http.Request, userID uuid.UUID) bool {
+	user, err := h.db.GetUserByID(r.Context(), userID)
+	return err == nil && user.IsChirpyRed
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+)
+
+// ReportChirp permite a un usuario reportar un chirp.
+func (h *Handler) ReportChirp(w http.ResponseWriter, r *http.Request) {
+	reporterID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidChirpID, err)
+		return
+	}
+
+	req, ok := decodeReportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.ReportChirp(r.Context(), reporterID, chirpID, req.Reason, req.Details)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusCreated, reportResponse(report))
+}
+
+// ReportUser permite a un usuario reportar a otro usuario.
+func (h *Handler) ReportUser(w http.ResponseWriter, r *http.Request) {
+	reporterID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := decodeReportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.ReportUser(r.Context(), reporterID, targetID, req.Reason, req.Details)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusCreated, reportResponse(report))
+}
+
+// ListReports devuelve la cola de moderación (por defecto, los reportes abiertos).
+func (h *Handler) ListReports(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireRole(w, r, RoleModerator); !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPagination, err)
+		return
+	}
+
+	reports, err := h.svc.ListReports(r.Context(), r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	response := []api.ReportResponse{}
+	for _, report := range reports {
+		response = append(response, reportResponse(report))
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// ResolveReport aplica una acción de moderación sobre un reporte abierto.
+func (h *Handler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.requireRole(w, r, RoleModerator)
+	if !ok {
+		return
+	}
+
+	reportID, err := uuid.Parse(r.PathValue("reportID"))
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidReportID, err)
+		return
+	}
+
+	var req api.ResolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	resolved, err := h.svc.ResolveReport(r.Context(), moderator.ID, reportID, req.Action, req.SuspendHours)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, reportResponse(resolved))
+}
+
+func decodeReportRequest(w http.ResponseWriter, r *http.Request) (api.ReportRequest, bool) {
+	var req api.ReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return req, false
+	}
+	if err := service.ValidateReport(req.Reason, req.Details); err != nil {
+		respondWithServiceError(w, r, err)
+		return req, false
+	}
+	return req, true
+}
+
+func reportResponse(report database.Report) api.ReportResponse {
+	response := api.ReportResponse{
+		ID:             report.ID,
+		CreatedAt:      report.CreatedAt,
+		UpdatedAt:      report.UpdatedAt,
+		ReporterID:     report.ReporterID,
+		ReportedUserID: report.ReportedUserID,
+		Reason:         report.Reason,
+		Details:        report.Details,
+		Status:         report.Status,
+		Resolution:     report.Resolution.String,
+	}
+	if report.ChirpID.Valid {
+		response.ChirpID = &report.ChirpID.UUID
+	}
+	if report.ResolvedBy.Valid {
+		response.ResolvedBy = &report.ResolvedBy.UUID
+	}
+	if report.ResolvedAt.Valid {
+		response.ResolvedAt = &report.ResolvedAt.Time
+	}
+	return response
+}
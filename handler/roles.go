@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/middleware"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+)
+
+// Roles disponibles para los usuarios
+const (
+	RoleUser      = service.RoleUser
+	RoleModerator = service.RoleModerator
+	RoleAdmin     = service.RoleAdmin
+)
+
+// authenticate valida el JWT del header Authorization y devuelve el ID del usuario.
+// Si falla, ya respondió al cliente y devuelve false.
+func (h *Handler) authenticate(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeMissingToken, err)
+		return uuid.Nil, false
+	}
+
+	userID, err := auth.ValidateJWT(token, h.jwtSecret)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidToken, err)
+		return uuid.Nil, false
+	}
+
+	middleware.SetUserID(r.Context(), userID)
+	return userID, true
+}
+
+// requireRole autentica al usuario y verifica que tenga al menos el rol indicado.
+func (h *Handler) requireRole(w http.ResponseWriter, r *http.Request, minRole string) (database.User, bool) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return database.User{}, false
+	}
+
+	user, err := h.currentUser(r, userID)
+	if database.IsNotFound(err) {
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidToken, nil)
+		return database.User{}, false
+	}
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
+		return database.User{}, false
+	}
+
+	if accountRestriction(user) != "" || !service.HasRole(user, minRole) {
+		api.RespondWithError(w, r, http.StatusForbidden, api.CodeInsufficientPermissions, nil)
+		return database.User{}, false
+	}
+
+	return user, true
+}
+
+// currentUser lee al usuario autenticado sin pasar por el caché: su rol y si está
+// baneado o suspendido deciden qué puede hacer, y otra réplica pudo cambiarlos.
+func (h *Handler) currentUser(r *http.Request, userID uuid.UUID) (database.User, error) {
+	return h.db.GetUserByID(service.FreshReads(r.Context()), userID)
+}
+
+// accountRestriction devuelve el código de error si la cuenta está eliminada, baneada o suspendida.
+func accountRestriction(user database.User) api.Code {
+	return serviceErrorCode(service.CheckAccount(user))
+}
+
+// optionalViewer devuelve el usuario autenticado si la petición incluye un JWT válido.
+// A diferencia de authenticate, nunca responde al cliente.
+func (h *Handler) optionalViewer(r *http.Request) (database.User, bool) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return database.User{}, false
+	}
+
+	userID, err := auth.ValidateJWT(token, h.jwtSecret)
+	if err != nil {
+		return database.User{}, false
+	}
+
+	user, err := h.currentUser(r, userID)
+	if err != nil {
+		return database.User{}, false
+	}
+	middleware.SetUserID(r.Context(), user.ID)
+	return user, true
+}
+
+// canViewChirp indica si el chirp es visible: los ocultos solo los ven su autor y los moderadores.
+func canViewChirp(chirp database.Chirp, viewer database.User, hasViewer bool) bool {
+	if !chirp.HiddenAt.Valid {
+		return true
+	}
+	if !hasViewer {
+		return false
+	}
+	return viewer.ID == chirp.UserID || service.HasRole(viewer, RoleModerator)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import "net/http"
+
+// RegisterRoutes registra en mux los endpoints de la API y de administración.
+// Health checks y métricas se registran aparte porque no dependen del Store.
+// Cada ruta tiene su política de rate limiting; las que no tienen una propia usan la estándar.
+// Las rutas POST que crean recursos o sesiones aceptan Idempotency-Key.
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	p := h.rateLimits
+
+	mux.Handle("POST /api/users", h.rateLimited(p.signup, h.idempotent(h.CreateUser)))
+	mux.Handle("POST /api/chirps", h.rateLimited(p.chirps, h.idempotent(h.CreateChirp)))
+	mux.Handle("GET /api/chirps", h.rateLimited(p.standard, h.PolkaGetChirps))
+	mux.Handle("POST /admin/reset", h.rateLimited(p.standard, h.ResetDatabase))
+	mux.Handle("GET /api/chirps/{chirpID}", h.rateLimited(p.standard, h.GetChirpByID))
+	mux.Handle("GET /api/stream/chirps", h.rateLimited(p.standard, h.StreamChirps))
+	mux.Handle("GET /api/ws", h.rateLimited(p.standard, h.WebSocket))
+	mux.Handle("POST /api/polka/webhooks", h.rateLimited(p.webhooks, h.PolkaWebhook))
+	mux.Handle("POST /api/login", h.rateLimited(p.login, h.Login))
+	mux.Handle("POST /api/refresh", h.rateLimited(p.standard, h.RefreshTokenHandler))
+	mux.Handle("POST /api/revoke", h.rateLimited(p.standard, h.RevokeTokenHandler))
+	mux.Handle("PUT /api/users", h.rateLimited(p.standard, h.UpdateUser))
+	mux.Handle("PATCH /api/users/me", h.rateLimited(p.standard, h.PatchMe))
+	mux.Handle("POST /api/users/verify-email", h.rateLimited(p.standard, h.VerifyEmail))
+	mux.Handle("GET /api/users/{username}", h.rateLimited(p.standard, h.GetUserProfile))
+	mux.Handle("PUT /api/users/me/profile", h.rateLimited(p.standard, h.UpdateProfile))
+	mux.Handle("DELETE /api/users", h.rateLimited(p.standard, h.DeleteAccount))
+	mux.Handle("GET /api/users/me/export", h.rateLimited(p.standard, h.ExportAccount))
+	mux.Handle("DELETE /api/chirps/{chirpID}", h.rateLimited(p.standard, h.DeleteChirp))
+	mux.Handle("POST /api/chirps/{chirpID}/report", h.rateLimited(p.standard, h.ReportChirp))
+	mux.Handle("POST /api/users/{userID}/report", h.rateLimited(p.standard, h.ReportUser))
+	mux.Handle("POST /api/chirps/{chirpID}/like", h.rateLimited(p.standard, h.LikeChirp))
+	mux.Handle("DELETE /api/chirps/{chirpID}/like", h.rateLimited(p.standard, h.UnlikeChirp))
+	mux.Handle("GET /api/users/me/following", h.rateLimited(p.standard, h.ListFollowing))
+	mux.Handle("GET /api/users/me/followers", h.rateLimited(p.standard, h.ListFollowers))
+	mux.Handle("POST /api/users/{userID}/follow", h.rateLimited(p.standard, h.FollowUser))
+	mux.Handle("DELETE /api/users/{userID}/follow", h.rateLimited(p.standard, h.UnfollowUser))
+	mux.Handle("GET /api/users/me/blocks", h.rateLimited(p.standard, h.ListBlocks))
+	mux.Handle("POST /api/users/{userID}/block", h.rateLimited(p.standard, h.BlockUser))
+	mux.Handle("DELETE /api/users/{userID}/block", h.rateLimited(p.standard, h.UnblockUser))
+	mux.Handle("GET /api/users/me/mutes", h.rateLimited(p.standard, h.ListMutes))
+	mux.Handle("POST /api/users/{userID}/mute", h.rateLimited(p.standard, h.MuteUser))
+	mux.Handle("DELETE /api/users/{userID}/mute", h.rateLimited(p.standard, h.UnmuteUser))
+	mux.Handle("GET /api/notifications", h.rateLimited(p.standard, h.ListNotifications))
+	mux.Handle("POST /api/notifications/read", h.rateLimited(p.standard, h.MarkAllNotificationsRead))
+	mux.Handle("POST /api/notifications/{notificationID}/read", h.rateLimited(p.standard, h.MarkNotificationRead))
+	mux.Handle("GET /api/notifications/preferences", h.rateLimited(p.standard, h.GetNotificationPreferences))
+	mux.Handle("PUT /api/notifications/preferences", h.rateLimited(p.standard, h.UpdateNotificationPreferences))
+
+	// Endpoints de administración y moderación
+	mux.Handle("GET /admin/users", h.rateLimited(p.standard, h.ListUsers))
+	mux.Handle("PUT /admin/users/{userID}/role", h.rateLimited(p.standard, h.UpdateUserRole))
+	mux.Handle("POST /admin/users/{userID}/suspend", h.rateLimited(p.standard, h.SuspendUser))
+	mux.Handle("POST /admin/users/{userID}/ban", h.rateLimited(p.standard, h.BanUser))
+	mux.Handle("POST /admin/users/{userID}/reinstate", h.rateLimited(p.standard, h.ReinstateUser))
+	mux.Handle("POST /admin/users/{userID}/chirpy-red", h.rateLimited(p.standard, h.GrantChirpyRed))
+	mux.Handle("DELETE /admin/users/{userID}/chirpy-red", h.rateLimited(p.standard, h.RevokeChirpyRed))
+	mux.Handle("DELETE /admin/chirps/{chirpID}", h.rateLimited(p.standard, h.AdminDeleteChirp))
+	mux.Handle("GET /admin/audit", h.rateLimited(p.standard, h.ListAuditLogs))
+	mux.Handle("GET /admin/reports", h.rateLimited(p.standard, h.ListReports))
+	mux.Handle("POST /admin/reports/{reportID}/resolve", h.rateLimited(p.standard, h.ResolveReport))
+}
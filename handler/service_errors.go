@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+)
+
+// serviceErrors traduce los errores de dominio del servicio a respuestas de la API.
+var serviceErrors = []struct {
+	err    error
+	status int
+	code   api.Code
+}{
+	{service.ErrInvalidCredentials, http.StatusUnauthorized, api.CodeInvalidCredentials},
+	{service.ErrIncorrectPassword, http.StatusUnauthorized, api.CodeIncorrectPassword},
+	{service.ErrAccountPendingDeletion, http.StatusForbidden, api.CodeAccountPendingDeletion},
+	{service.ErrAccountBanned, http.StatusForbidden, api.CodeAccountBanned},
+	{service.ErrAccountSuspended, http.StatusForbidden, api.CodeAccountSuspended},
+	{service.ErrAccountAlreadyDeleted, http.StatusConflict, api.CodeAccountAlreadyDeleted},
+	{service.ErrUserNotFound, http.StatusNotFound, api.CodeUserNotFound},
+	{service.ErrEmailInUse, http.StatusConflict, api.CodeEmailInUse},
+	{service.ErrUsernameTaken, http.StatusConflict, api.CodeUsernameTaken},
+	{service.ErrInvalidVerificationToken, http.StatusBadRequest, api.CodeInvalidVerificationToken},
+	{service.ErrInvalidRole, http.StatusBadRequest, api.CodeInvalidRole},
+	{service.ErrInvalidSuspension, http.StatusBadRequest, api.CodeInvalidSuspension},
+	{service.ErrChirpNotFound, http.StatusNotFound, api.CodeChirpNotFound},
+	{service.ErrNotChirpOwner, http.StatusForbidden, api.CodeNotChirpOwner},
+	{service.ErrChirpModified, http.StatusPreconditionFailed, api.CodePreconditionFailed},
+	{service.ErrMentionBlocked, http.StatusForbidden, api.CodeMentionBlocked},
+	{service.ErrCannotTargetSelf, http.StatusBadRequest, api.CodeCannotTargetSelf},
+	{service.ErrBlockedByUser, http.StatusForbidden, api.CodeBlockedByUser},
+	{service.ErrReportNotFound, http.StatusNotFound, api.CodeReportNotFound},
+	{service.ErrInvalidReportStatus, http.StatusBadRequest, api.CodeInvalidReportStatus},
+	{service.ErrReportAlreadyResolved, http.StatusConflict, api.CodeReportAlreadyResolved},
+	{service.ErrReportHasNoChirp, http.StatusBadRequest, api.CodeReportHasNoChirp},
+	{service.ErrInvalidReportReason, http.StatusBadRequest, api.CodeInvalidReportReason},
+	{service.ErrReportDetailsTooLong, http.StatusBadRequest, api.CodeReportDetailsTooLong},
+	{service.ErrInvalidModerationAction, http.StatusBadRequest, api.CodeInvalidModerationAction},
+	{service.ErrCannotModerateUser, http.StatusForbidden, api.CodeCannotModerateUser},
+	{service.ErrInvalidNotificationType, http.StatusBadRequest, api.CodeInvalidNotificationType},
+	{service.ErrNotificationNotFound, http.StatusNotFound, api.CodeNotificationNotFound},
+}
+
+// respondWithServiceError responde al error de una operación del servicio. Los errores
+// de base de datos sin traducción de dominio se responden con api.RespondWithDBError.
+func respondWithServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, service.ErrChirpTooLong) {
+		api.RespondWithValidationErrors(w, r, api.FieldError{Field: "body", Code: api.CodeChirpTooLong})
+		return
+	}
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			api.RespondWithError(w, r, known.status, known.code, nil)
+			return
+		}
+	}
+	api.RespondWithDBError(w, r, api.CodeNotFound, err)
+}
+
+// serviceErrorCode devuelve el código de la API de un error de dominio, o "" si no lo es.
+func serviceErrorCode(err error) api.Code {
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			return known.code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// LikeChirp da like a un chirp en nombre del usuario autenticado.
+func (h *Handler) LikeChirp(w http.ResponseWriter, r *http.Request) {
+	userID, chirpID, ok := h.likeTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.LikeChirp(r.Context(), userID, chirpID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlikeChirp quita el like del usuario autenticado a un chirp.
+func (h *Handler) UnlikeChirp(w http.ResponseWriter, r *http.Request) {
+	userID, chirpID, ok := h.likeTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.UnlikeChirp(r.Context(), userID, chirpID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FollowUser hace que el usuario autenticado siga a otro usuario.
+func (h *Handler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := h.followTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.FollowUser(r.Context(), userID, targetID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnfollowUser hace que el usuario autenticado deje de seguir a otro usuario.
+func (h *Handler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := h.followTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.UnfollowUser(r.Context(), userID, targetID); err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListFollowing devuelve los usuarios a los que sigue el usuario autenticado.
+func (h *Handler) ListFollowing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	follows, err := h.svc.ListFollowing(r.Context(), userID)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, followingResponse(follows))
+}
+
+// ListFollowers devuelve los usuarios que siguen al usuario autenticado.
+func (h *Handler) ListFollowers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	follows, err := h.svc.ListFollowers(r.Context(), userID)
+	if err != nil {
+		respondWithServiceError(w, r, err)
+		return
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, followersResponse(follows))
+}
+
+// followedAuthors devuelve los autores del timeline de userID: userID y quienes sigue.
+func (h *Handler) followedAuthors(r *http.Request, userID uuid.UUID) (map[uuid.UUID]struct{}, error) {
+	follows, err := h.svc.ListFollowing(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	followed := map[uuid.UUID]struct{}{userID: {}}
+	for _, follow := range follows {
+		followed[follow.FollowedID] = struct{}{}
+	}
+	return followed, nil
+}
+
+// likeTarget autentica al usuario y valida el {chirpID} de la ruta.
+func (h *Handler) likeTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidChirpID, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, chirpID, true
+}
+
+// followTarget autentica al usuario y valida el {userID} de la ruta. Que el usuario
+// exista y no sea el propio lo comprueba el servicio.
+func (h *Handler) followTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	targetID, ok := parseUserIDParam(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, targetID, true
+}
+
+// followingResponse lista a los usuarios seguidos.
+func followingResponse(follows []database.Follow) []api.UserRelationship {
+	response := []api.UserRelationship{}
+	for _, follow := range follows {
+		response = append(response, api.UserRelationship{
+			UserID:    follow.FollowedID,
+			CreatedAt: follow.CreatedAt,
+		})
+	}
+	return response
+}
+
+// followersResponse lista a los seguidores.
+func followersResponse(follows []database.Follow) []api.UserRelationship {
+	response := []api.UserRelationship{}
+	for _, follow := range follows {
+		response = append(response, api.UserRelationship{
+			UserID:    follow.FollowerID,
+			CreatedAt: follow.CreatedAt,
+		})
+	}
+	return response
+}
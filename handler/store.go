@@ -0,0 +1,7 @@
+package handler
+
+import "github.com/amadrigalIstmo/Chirpy-project/internal/service"
+
+// Store es la base de datos de los handlers: las consultas que usan directamente para
+// lecturas y operaciones de un solo paso, y las transacciones del servicio.
+type Store = service.Store
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/google/uuid"
+)
+
+const (
+	// streamHistory es cuántos eventos recientes se guardan para reanudar con Last-Event-ID.
+	streamHistory = 1000
+	// streamBuffer es cuántos eventos puede tener pendientes un cliente antes de desconectarlo.
+	streamBuffer = 64
+	// streamHeartbeat es cada cuánto se envía un comentario para que los proxies no
+	// cierren la conexión y para detectar clientes que ya no están.
+	streamHeartbeat = 15 * time.Second
+	// streamWriteTimeout es el plazo de cada escritura; el stream no tiene plazo total.
+	streamWriteTimeout = 10 * time.Second
+	// streamRetry es cuánto espera el navegador antes de reconectarse (campo retry de SSE).
+	streamRetry = 3 * time.Second
+)
+
+// Events devuelve el broker de eventos en tiempo real, para conectarle un Fanout entre
+// réplicas o cerrarlo en el apagado.
+func (h *Handler) Events() *events.Broker {
+	return h.events
+}
+
+// StreamChirps envía por Server-Sent Events los chirps que se publican (chirp.created)
+// y los que dejan de ser públicos (chirp.deleted). Acepta ?author_id= para seguir a un
+// solo autor, ?following=true para recibir solo el timeline del usuario autenticado (sus
+// chirps y los de quienes sigue), y Last-Event-ID para recuperar lo que el cliente se perdió mientras estaba
+// desconectado, si sigue en el historial. Con un JWT se omiten, como en el listado, los
+// chirps de autores que el lector silenció o bloqueó y de los que lo bloquearon.
+func (h *Handler) StreamChirps(w http.ResponseWriter, r *http.Request) {
+	viewer, hasViewer := h.optionalViewer(r)
+	hidden := map[uuid.UUID]struct{}{}
+	if hasViewer {
+		var err error
+		// Como en /api/ws, los cambios posteriores se aplican al reconectar
+		hidden, err = h.hiddenAuthors(r, viewer.ID)
+		if err != nil {
+			api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
+			return
+		}
+	}
+	visible := func(chirp database.Chirp) bool {
+		if _, ok := hidden[chirp.UserID]; ok {
+			return false
+		}
+		return canViewChirp(chirp, viewer, hasViewer)
+	}
+
+	// author decide qué autores recibe el cliente; se evalúa en el broker, antes de encolar
+	author := func(uuid.UUID) bool { return true }
+	if raw := r.URL.Query().Get("author_id"); raw != "" {
+		authorID, err := uuid.Parse(raw)
+		if err != nil {
+			api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidAuthorID, err)
+			return
+		}
+		author = func(id uuid.UUID) bool { return id == authorID }
+	}
+	if r.URL.Query().Get("following") == "true" {
+		if !hasViewer {
+			api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeMissingToken, nil)
+			return
+		}
+		// Igual que los silenciados, los follows nuevos se aplican al reconectar
+		followed, err := h.followedAuthors(r, viewer.ID)
+		if err != nil {
+			api.RespondWithDBError(w, r, api.CodeNotFound, err)
+			return
+		}
+		byAuthorID := author
+		author = func(id uuid.UUID) bool {
+			_, ok := followed[id]
+			return ok && byAuthorID(id)
+		}
+	}
+	filter := func(e events.Event) bool { return e.IsChirpEvent() && author(e.AuthorID) }
+
+	sub, missed, err := h.events.Subscribe(r.Header.Get("Last-Event-ID"), filter)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusServiceUnavailable, api.CodeServiceUnavailable, err)
+		return
+	}
+	defer h.events.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Cada escritura tiene su propio plazo en lugar del server.write_timeout de la petición:
+	// así el stream puede durar horas pero un cliente que no lee no bloquea para siempre.
+	// El plazo de lectura también se quita: al vencer, el servidor cancelaría la petición
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return
+	}
+	write := func(format string, args ...any) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := write("retry: %d\n\n", streamRetry.Milliseconds()); err != nil {
+		return
+	}
+	for _, e := range missed {
+		if err := h.writeEvent(r, write, e, visible); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events():
+			// El broker cerró la suscripción (cliente lento o apagado): el cliente se
+			// reconecta con Last-Event-ID
+			if !ok {
+				return
+			}
+			if err := h.writeEvent(r, write, e, visible); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := write(": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent escribe e en formato SSE. Si el lector no puede ver el chirp (visible
+// devuelve false), el evento se omite.
+func (h *Handler) writeEvent(r *http.Request, write func(format string, args ...any) error, e events.Event, visible func(database.Chirp) bool) error {
+	var data any
+	switch e.Type {
+	case events.ChirpCreated:
+		if e.Chirp == nil || !visible(*e.Chirp) {
+			return nil
+		}
+		data = chirpResponse(*e.Chirp, nil)
+	case events.ChirpDeleted:
+		data = api.ChirpDeletedEvent{ID: e.ChirpID, UserID: e.AuthorID}
+	default:
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return write("id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, payload)
+}
@@ -1,60 +1,173 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/amadrigalIstmo/Chirpy-project/api"
-	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
 )
 
+// UpdateUser reemplaza email y contraseña del usuario autenticado. Aplica las mismas reglas
+// que PatchMe: exige la contraseña actual y el nuevo email queda pendiente de verificación.
 func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
-	// Definir estructura para los parámetros esperados
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	var req api.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
 		return
 	}
 
-	// Obtener el token JWT desde los encabezados
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+	var fieldErrs []api.FieldError
+	if req.Email == "" {
+		fieldErrs = append(fieldErrs, api.FieldError{Field: "email", Code: api.CodeRequired})
+	}
+	if req.Password == "" {
+		fieldErrs = append(fieldErrs, api.FieldError{Field: "password", Code: api.CodeRequired})
+	}
+	if len(fieldErrs) > 0 {
+		api.RespondWithValidationErrors(w, r, fieldErrs...)
 		return
 	}
 
-	// Validar el JWT y obtener el ID del usuario autenticado
-	userID, err := auth.ValidateJWT(token, h.jwtSecret)
-	if err != nil {
-		api.RespondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+	h.patchUser(w, r, userID, api.PatchUserRequest{
+		Email:           &req.Email,
+		Password:        &req.Password,
+		CurrentPassword: req.CurrentPassword,
+	})
+}
+
+// PatchMe actualiza parcialmente al usuario autenticado: solo cambian los campos enviados.
+// Cambiar la contraseña exige la contraseña actual y cambiar el email exige verificarlo.
+func (h *Handler) PatchMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
 		return
 	}
 
-	// Hashear la nueva contraseña
-	hashedPassword, err := auth.HashPassword(req.Password)
+	var req api.PatchUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	if req.Email == nil && req.Password == nil && req.Username == nil &&
+		req.DisplayName == nil && req.Bio == nil && req.AvatarURL == nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeNoFieldsToUpdate, nil)
+		return
+	}
+
+	h.patchUser(w, r, userID, req)
+}
+
+// patchUser valida los campos presentes en req, los aplica al usuario y responde con el
+// resultado. Si cambia el email, envía el token de verificación al nuevo correo.
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request, userID uuid.UUID, req api.PatchUserRequest) {
+	changes := service.UserChanges{
+		Password:        req.Password,
+		CurrentPassword: req.CurrentPassword,
+		Username:        req.Username,
+		AvatarURL:       req.AvatarURL,
+	}
+
+	if req.Password != nil && *req.Password == "" {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodePasswordEmpty, nil)
+		return
+	}
+
+	if req.Username != nil {
+		if fieldErr := validateUsername(*req.Username); fieldErr != nil {
+			api.RespondWithValidationErrors(w, r, *fieldErr)
+			return
+		}
+	}
+
+	// Solo se validan los campos enviados: los guardados ya pasaron la validación
+	var displayName, bio, avatarURL string
+	if req.DisplayName != nil {
+		displayName = strings.TrimSpace(*req.DisplayName)
+		changes.DisplayName = &displayName
+	}
+	if req.Bio != nil {
+		bio = strings.TrimSpace(*req.Bio)
+		changes.Bio = &bio
+	}
+	if req.AvatarURL != nil {
+		avatarURL = *req.AvatarURL
+	}
+	if fieldErrs := validateProfileFields(displayName, bio, avatarURL); len(fieldErrs) > 0 {
+		api.RespondWithValidationErrors(w, r, fieldErrs...)
+		return
+	}
+
+	if req.Email != nil {
+		if !strings.Contains(service.NormalizeEmail(*req.Email), "@") {
+			api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidEmail, nil)
+			return
+		}
+		changes.Email = req.Email
+	}
+
+	user, verification, err := h.svc.UpdateUser(r.Context(), userID, changes)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Couldn't hash password", err)
+		respondWithServiceError(w, r, err)
 		return
 	}
 
-	// Actualizar usuario en la base de datos
-	updatedUser, err := h.db.UpdateUser(r.Context(), database.UpdateUserParams{
-		ID:             userID,
-		Email:          req.Email,
-		HashedPassword: hashedPassword,
-	})
+	// El nuevo email no se aplica hasta que el usuario lo verifique
+	if verification.Token != "" {
+		if err := h.sendEmailVerification(r.Context(), verification); err != nil {
+			api.RespondWithError(w, r, http.StatusInternalServerError, api.CodeInternalError, err)
+			return
+		}
+	}
+
+	api.RespondWithJSON(w, http.StatusOK, userResponse(user, verification.Email))
+}
+
+// VerifyEmail confirma un cambio de email con el token enviado al nuevo correo.
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req api.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+		return
+	}
+
+	user, err := h.svc.VerifyEmail(r.Context(), req.Token)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Couldn't update user", err)
+		respondWithServiceError(w, r, err)
 		return
 	}
 
-	// Responder con los datos actualizados del usuario (sin la contraseña)
-	api.RespondWithJSON(w, http.StatusOK, api.UpdateUserResponse{
-		ID:          updatedUser.ID,
-		CreatedAt:   updatedUser.CreatedAt,
-		UpdatedAt:   updatedUser.UpdatedAt,
-		Email:       updatedUser.Email,
-		IsChirpyRed: updatedUser.IsChirpyRed,
-	})
+	api.RespondWithJSON(w, http.StatusOK, userResponse(user, ""))
+}
+
+// sendEmailVerification envía el token de la verificación al nuevo email.
+func (h *Handler) sendEmailVerification(ctx context.Context, verification database.EmailVerification) error {
+	body := fmt.Sprintf("Confirm your new Chirpy email by sending this token to POST /api/users/verify-email:\n\n%s\n", verification.Token)
+	return h.mailer.Send(ctx, verification.Email, "Confirm your new email", body)
+}
+
+func userResponse(user database.User, pendingEmail string) api.UpdateUserResponse {
+	return api.UpdateUserResponse{
+		ID:           user.ID,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+		Email:        user.Email,
+		PendingEmail: pendingEmail,
+		Username:     user.Username.String,
+		DisplayName:  user.DisplayName,
+		Bio:          user.Bio,
+		AvatarURL:    user.AvatarUrl,
+		IsChirpyRed:  user.IsChirpyRed,
+	}
 }
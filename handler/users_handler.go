@@ -1,31 +1,58 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/amadrigalIstmo/Chirpy-project/api"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
 )
 
 // CreateUser maneja la creación de un usuario
 func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if !h.signupsEnabled {
+		api.RespondWithError(w, r, http.StatusForbidden, api.CodeSignupsDisabled, nil)
+		return
+	}
+
 	var req api.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
 		return
 	}
 
-	if req.Email == "" || req.Password == "" {
-		api.RespondWithError(w, http.StatusBadRequest, "Email and password are required", nil)
+	req.Email = service.NormalizeEmail(req.Email)
+
+	var fieldErrs []api.FieldError
+	if req.Email == "" {
+		fieldErrs = append(fieldErrs, api.FieldError{Field: "email", Code: api.CodeRequired})
+	}
+	if req.Password == "" {
+		fieldErrs = append(fieldErrs, api.FieldError{Field: "password", Code: api.CodeRequired})
+	}
+
+	// El username es opcional al registrarse, pero si viene debe ser válido
+	username := sql.NullString{}
+	if req.Username != "" {
+		if fieldErr := validateUsername(req.Username); fieldErr != nil {
+			fieldErrs = append(fieldErrs, *fieldErr)
+		}
+		username = sql.NullString{String: req.Username, Valid: true}
+	}
+	fieldErrs = append(fieldErrs, validateProfileFields(req.DisplayName, "", "")...)
+	if len(fieldErrs) > 0 {
+		api.RespondWithValidationErrors(w, r, fieldErrs...)
 		return
 	}
 
 	// Hash de la contraseña antes de almacenarla
 	hashedPassword, err := auth.HashPassword(req.Password)
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Could not hash password", err)
+		api.RespondWithError(w, r, http.StatusInternalServerError, api.CodeInternalError, err)
 		return
 	}
 
@@ -33,9 +60,15 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	newUser, err := h.db.CreateUser(r.Context(), database.CreateUserParams{
 		Email:          req.Email,
 		HashedPassword: hashedPassword,
+		Username:       username,
+		DisplayName:    strings.TrimSpace(req.DisplayName),
 	})
+	if database.IsConflict(err) {
+		api.RespondWithError(w, r, http.StatusConflict, api.CodeAccountExists, nil)
+		return
+	}
 	if err != nil {
-		api.RespondWithError(w, http.StatusInternalServerError, "Could not create user", err)
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
 		return
 	}
 
@@ -45,6 +78,7 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:   newUser.CreatedAt,
 		UpdatedAt:   newUser.UpdatedAt,
 		Email:       newUser.Email,
+		Username:    newUser.Username.String,
 		IsChirpyRed: newUser.IsChirpyRed,
 	}
 
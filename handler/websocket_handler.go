@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsMaxTopics es cuántos temas puede tener suscritos una conexión.
+	wsMaxTopics = 100
+	// wsMaxMessageBytes es el tamaño máximo de un mensaje del cliente.
+	wsMaxMessageBytes = 4096
+	// wsBuffer es cuántos eventos puede tener pendientes una conexión antes de cerrarla.
+	wsBuffer = 64
+	// wsPingInterval es cada cuánto se envía un ping; si no llega el pong en wsPongWait
+	// la conexión se da por perdida.
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 2 * wsPingInterval
+	// wsWriteTimeout es el plazo de cada escritura.
+	wsWriteTimeout = 10 * time.Second
+)
+
+// Temas a los que se puede suscribir una conexión
+const (
+	topicGlobal        = "global"
+	topicNotifications = "notifications"
+	topicUserPrefix    = "user:"
+	topicHashtagPrefix = "hashtag:"
+)
+
+// La autenticación va en el JWT y no en cookies, así que una página de otro origen no
+// puede abrir la conexión en nombre del usuario: se aceptan todos los orígenes.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// WebSocket abre un canal bidireccional en el que el cliente se suscribe a temas y
+// recibe sus eventos:
+//   - global: todos los chirps;
+//   - user:<id>: los chirps de un autor;
+//   - hashtag:<tag>: los chirps con ese hashtag;
+//   - notifications: las notificaciones del usuario autenticado.
+//
+// Los navegadores no pueden enviar el header Authorization al abrir un WebSocket, así que
+// el JWT también se acepta en ?access_token=. Los chirps de autores bloqueados o
+// silenciados no se envían, igual que en GET /api/chirps.
+func (h *Handler) WebSocket(w http.ResponseWriter, r *http.Request) {
+	if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	viewer, err := h.currentUser(r, userID)
+	if database.IsNotFound(err) {
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidToken, nil)
+		return
+	}
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
+		return
+	}
+	if code := accountRestriction(viewer); code != "" {
+		api.RespondWithError(w, r, http.StatusForbidden, code, nil)
+		return
+	}
+
+	// Los silenciados, los bloqueados y quienes bloquearon al lector se leen al conectarse;
+	// los cambios posteriores se aplican al reconectar.
+	hidden, err := h.hiddenAuthors(r, viewer.ID)
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeUserNotFound, err)
+		return
+	}
+
+	s := &wsSession{
+		h:       h,
+		r:       r,
+		viewer:  viewer,
+		lang:    api.RequestLanguage(r),
+		hidden:  hidden,
+		topics:  make(map[string]struct{}),
+		replies: make(chan api.WSMessage, wsBuffer),
+		done:    make(chan struct{}),
+	}
+	sub, _, err := h.events.Subscribe("", s.wants)
+	if err != nil {
+		api.RespondWithError(w, r, http.StatusServiceUnavailable, api.CodeServiceUnavailable, err)
+		return
+	}
+	defer h.events.Unsubscribe(sub)
+
+	// Upgrade ya respondió al cliente si falla
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	s.conn = conn
+
+	go s.readLoop()
+	s.writeLoop(sub)
+}
+
+// wsSession es una conexión abierta en /api/ws. readLoop atiende los mensajes del
+// cliente y writeLoop es el único que escribe en la conexión.
+type wsSession struct {
+	h      *Handler
+	r      *http.Request
+	conn   *websocket.Conn
+	viewer database.User
+	lang   string
+	hidden map[uuid.UUID]struct{}
+
+	mu     sync.RWMutex
+	topics map[string]struct{}
+
+	replies chan api.WSMessage
+	// done se cierra cuando readLoop termina; closeCode y closeText dicen por qué.
+	done      chan struct{}
+	closeCode int
+	closeText string
+}
+
+// readLoop lee los mensajes del cliente hasta que se cierra la conexión.
+func (s *wsSession) readLoop() {
+	defer close(s.done)
+
+	s.conn.SetReadLimit(wsMaxMessageBytes)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				s.closeCode, s.closeText = websocket.CloseMessageTooBig, "message too big"
+			}
+			return
+		}
+
+		var req api.WSRequest
+		var reply api.WSMessage
+		if err := json.Unmarshal(data, &req); err != nil {
+			reply = s.errorMessage("", api.CodeInvalidPayload)
+		} else {
+			reply = s.handle(req)
+		}
+
+		// Un cliente que envía más rápido de lo que lee sus respuestas se desconecta
+		select {
+		case s.replies <- reply:
+		default:
+			s.closeCode, s.closeText = websocket.ClosePolicyViolation, "too many pending replies"
+			return
+		}
+	}
+}
+
+// handle aplica un mensaje del cliente y devuelve la respuesta.
+func (s *wsSession) handle(req api.WSRequest) api.WSMessage {
+	switch req.Type {
+	case "ping":
+		return api.WSMessage{Type: "pong", ID: req.ID}
+	case "subscribe", "unsubscribe":
+	default:
+		return s.errorMessage(req.ID, api.CodeInvalidMessageType)
+	}
+
+	topic, ok := parseTopic(req.Topic)
+	if !ok {
+		return s.errorMessage(req.ID, api.CodeInvalidTopic)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.Type == "unsubscribe" {
+		delete(s.topics, topic)
+		return api.WSMessage{Type: "unsubscribed", ID: req.ID, Topic: topic}
+	}
+	if _, ok := s.topics[topic]; !ok && len(s.topics) >= wsMaxTopics {
+		return s.errorMessage(req.ID, api.CodeTooManyTopics)
+	}
+	s.topics[topic] = struct{}{}
+	return api.WSMessage{Type: "subscribed", ID: req.ID, Topic: topic}
+}
+
+func (s *wsSession) errorMessage(id string, code api.Code) api.WSMessage {
+	return api.WSMessage{Type: "error", ID: id, Code: code, Detail: api.Message(s.lang, code)}
+}
+
+// parseTopic valida un tema y lo devuelve normalizado.
+func parseTopic(topic string) (string, bool) {
+	switch {
+	case topic == topicGlobal || topic == topicNotifications:
+		return topic, true
+	case strings.HasPrefix(topic, topicUserPrefix):
+		userID, err := uuid.Parse(strings.TrimPrefix(topic, topicUserPrefix))
+		return topicUserPrefix + userID.String(), err == nil
+	case strings.HasPrefix(topic, topicHashtagPrefix):
+		tag := strings.TrimPrefix(strings.TrimPrefix(topic, topicHashtagPrefix), "#")
+		tags := service.ExtractHashtags("#" + tag)
+		if len(tags) != 1 || tags[0] != strings.ToLower(tag) {
+			return "", false
+		}
+		return topicHashtagPrefix + tags[0], true
+	}
+	return "", false
+}
+
+// wants es el filtro de la suscripción al broker: el evento pertenece a algún tema suscrito.
+func (s *wsSession) wants(e events.Event) bool {
+	return len(s.topicsFor(e)) > 0
+}
+
+// topicsFor devuelve los temas suscritos a los que pertenece e.
+func (s *wsSession) topicsFor(e events.Event) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var topics []string
+	add := func(topic string) {
+		if _, ok := s.topics[topic]; ok {
+			topics = append(topics, topic)
+		}
+	}
+	switch {
+	case e.IsChirpEvent():
+		add(topicGlobal)
+		add(topicUserPrefix + e.AuthorID.String())
+		for _, tag := range e.Hashtags {
+			add(topicHashtagPrefix + tag)
+		}
+	case e.Type == events.NotificationCreated && e.RecipientID == s.viewer.ID:
+		add(topicNotifications)
+	}
+	return topics
+}
+
+// writeLoop envía las respuestas, los eventos y los pings hasta que la conexión termina.
+func (s *wsSession) writeLoop(sub *events.Subscription) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			if s.closeCode != 0 {
+				s.close(s.closeCode, s.closeText)
+			}
+			return
+		case reply := <-s.replies:
+			if err := s.write(reply); err != nil {
+				return
+			}
+		case e, ok := <-sub.Events():
+			if !ok {
+				// Un cliente que no lee a tiempo se desconecta para no retener eventos; puede
+				// reconectarse y pedir lo que se perdió por la API REST
+				if errors.Is(sub.Err(), events.ErrSlowConsumer) {
+					s.close(websocket.CloseTryAgainLater, "slow consumer")
+				} else {
+					s.close(websocket.CloseGoingAway, "server shutting down")
+				}
+				return
+			}
+			if err := s.writeEvent(e); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) write(msg api.WSMessage) error {
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return s.conn.WriteJSON(msg)
+}
+
+func (s *wsSession) close(code int, text string) {
+	s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(wsWriteTimeout))
+}
+
+// writeEvent envía e con los temas a los que pertenece. Los chirps que el usuario no
+// debe ver se omiten.
+func (s *wsSession) writeEvent(e events.Event) error {
+	topics := s.topicsFor(e)
+	if len(topics) == 0 {
+		return nil
+	}
+
+	var data any
+	switch e.Type {
+	case events.ChirpCreated:
+		if _, ok := s.hidden[e.AuthorID]; ok || e.Chirp == nil || !canViewChirp(*e.Chirp, s.viewer, true) {
+			return nil
+		}
+		data = chirpResponse(*e.Chirp, nil)
+	case events.ChirpDeleted:
+		data = api.ChirpDeletedEvent{ID: e.ChirpID, UserID: e.AuthorID}
+	case events.NotificationCreated:
+		if e.Notification == nil {
+			return nil
+		}
+		var actor *api.Author
+		if e.Notification.ActorID.Valid {
+			if user, err := s.h.db.GetUserByID(s.r.Context(), e.Notification.ActorID.UUID); err == nil {
+				actor = authorResponse(user.ID, user.Username, user.DisplayName, user.AvatarUrl)
+			}
+		}
+		data = notificationResponse(*e.Notification, actor)
+	default:
+		return nil
+	}
+
+	return s.write(api.WSMessage{
+		Type:    "event",
+		Topics:  topics,
+		Event:   e.Type,
+		EventID: e.ID,
+		Data:    data,
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/amadrigalIstmo/Chirpy-project/api"
 	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
 	"github.com/google/uuid"
 )
 
@@ -14,7 +15,7 @@ func (h *Handler) PolkaWebhook(w http.ResponseWriter, r *http.Request) {
 	// 🔹 Validamos la API Key
 	apiKey, err := auth.GetAPIKey(r.Header)
 	if err != nil || apiKey != h.polkaKey {
-		api.RespondWithError(w, http.StatusUnauthorized, "Invalid API Key", nil)
+		api.RespondWithError(w, r, http.StatusUnauthorized, api.CodeInvalidAPIKey, nil)
 		return
 	}
 
@@ -27,12 +28,13 @@ func (h *Handler) PolkaWebhook(w http.ResponseWriter, r *http.Request) {
 
 	var req WebhookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
 		return
 	}
 
-	// Ignorar eventos que no sean "user.upgraded"
+	// Ignorar eventos que no sean "user.upgraded" (se agrupan como "other" en las métricas)
 	if req.Event != "user.upgraded" {
+		metrics.WebhooksProcessed.WithLabelValues("other").Inc()
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
@@ -40,17 +42,18 @@ func (h *Handler) PolkaWebhook(w http.ResponseWriter, r *http.Request) {
 	// Validar el formato del UUID
 	userID, err := uuid.Parse(req.Data.UserID)
 	if err != nil {
-		api.RespondWithError(w, http.StatusBadRequest, "Invalid user ID format", err)
+		api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidUserID, err)
 		return
 	}
 
-	// Actualizar el usuario a Chirpy Red
-	_, err = h.db.UpgradeToChirpyRed(r.Context(), userID)
-	if err != nil {
-		api.RespondWithError(w, http.StatusNotFound, "User not found", err)
+	// Actualizar el usuario a Chirpy Red y guardar el evento en su historial de suscripción
+	if err := h.svc.UpgradeToChirpyRed(r.Context(), userID, req.Event, "polka"); err != nil {
+		respondWithServiceError(w, r, err)
 		return
 	}
 
+	metrics.WebhooksProcessed.WithLabelValues(req.Event).Inc()
+
 	// Responder con 204 No Content si la actualización fue exitosa
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -109,6 +109,11 @@ func MakeRefreshToken() (string, error) {
 	return hex.EncodeToString(token), nil
 }
 
+// MakeVerificationToken genera un token aleatorio de un solo uso para verificar emails
+func MakeVerificationToken() (string, error) {
+	return MakeRefreshToken()
+}
+
 // GetAPIKey extrae la clave API del encabezado Authorization
 func GetAPIKey(headers http.Header) (string, error) {
 	authHeader := headers.Get("Authorization")
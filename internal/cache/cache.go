@@ -0,0 +1,119 @@
+// Package cache implementa un caché de lectura delante de la base de datos para las
+// consultas más frecuentes (chirps y usuarios por ID).
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache es un almacén clave-valor con expiración. Los valores son bytes opacos para que
+// un adaptador de Redis pueda implementarla con GET, SET ... PX, DEL y FLUSHDB.
+type Cache interface {
+	// Get devuelve el valor de key y false si no existe o expiró.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set guarda value en key durante ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete borra las claves indicadas; las que no existen se ignoran.
+	Delete(ctx context.Context, keys ...string) error
+	// Clear borra todas las claves.
+	Clear(ctx context.Context) error
+}
+
+// LRU es un Cache en memoria del proceso. Cuando se llena descarta la clave usada hace
+// más tiempo, y las claves expiradas se descartan al leerlas. Es seguro para uso concurrente.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // del más reciente al menos reciente
+
+	now func() time.Time
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU crea un LRU que guarda como máximo capacity claves.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := elem.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(elem)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return e.value, true, nil
+}
+
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &entry{key: key, value: append([]byte(nil), value...), expiresAt: c.now().Add(ttl)}
+	if elem, ok := c.items[key]; ok {
+		elem.Value = e
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(e)
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRU) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+func (c *LRU) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+	return nil
+}
+
+// Len devuelve cuántas claves hay guardadas, incluidas las expiradas que aún no se leyeron.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove debe llamarse con el mutex tomado.
+func (c *LRU) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry).key)
+}
@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/memstore"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	c.Get(ctx, "a") // "b" pasa a ser la menos usada
+	c.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error(`Get("b") found an evicted key`)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := c.Get(ctx, key); !ok {
+			t.Errorf("Get(%q) missed a recently used key", key)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	if value, ok, _ := c.Get(ctx, "a"); !ok || string(value) != "1" {
+		t.Fatalf(`Get("a") = %q, %v before expiry`, value, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error(`Get("a") found an expired key`)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want expired key removed", c.Len())
+	}
+}
+
+func TestStoreInvalidatesOnWrite(t *testing.T) {
+	ctx := context.Background()
+	db := memstore.New()
+	lru := NewLRU(10)
+	s := NewStore(db, lru, time.Minute)
+
+	walt, _ := db.CreateUser(ctx, database.CreateUserParams{Email: "walt@breakingbad.com"})
+	chirp, _ := db.CreateChirp(ctx, database.CreateChirpParams{Body: "Say my name", UserID: walt.ID})
+
+	if _, err := s.GetUserByID(ctx, walt.ID); err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+	if _, err := s.GetChirp(ctx, chirp.ID); err != nil {
+		t.Fatalf("GetChirp() error = %v", err)
+	}
+	if lru.Len() != 2 {
+		t.Fatalf("cached entries = %d, want 2", lru.Len())
+	}
+
+	// Escritura directa
+	if _, err := s.BanUser(ctx, walt.ID); err != nil {
+		t.Fatalf("BanUser() error = %v", err)
+	}
+	if user, _ := s.GetUserByID(ctx, walt.ID); !user.BannedAt.Valid {
+		t.Error("GetUserByID() served a stale user after BanUser")
+	}
+
+	// Escritura en una transacción: se invalida aunque la lectura previa la haya cacheado
+	err := s.InTx(ctx, func(q service.Queries) error {
+		return q.DeleteChirp(ctx, chirp.ID)
+	})
+	if err != nil {
+		t.Fatalf("InTx() error = %v", err)
+	}
+	if _, err := s.GetChirp(ctx, chirp.ID); !database.IsNotFound(err) {
+		t.Errorf("GetChirp() after delete error = %v, want not found", err)
+	}
+
+	// Reset borra en cascada: se vacía todo el caché
+	s.GetUserByID(ctx, walt.ID)
+	if err := s.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if lru.Len() != 0 {
+		t.Errorf("cached entries after Reset = %d, want 0", lru.Len())
+	}
+}
+
+// brokenCache falla en todas las operaciones.
+type brokenCache struct{}
+
+var errBroken = errors.New("cache unavailable")
+
+func (brokenCache) Get(context.Context, string) ([]byte, bool, error) { return nil, false, errBroken }
+func (brokenCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errBroken
+}
+func (brokenCache) Delete(context.Context, ...string) error { return errBroken }
+func (brokenCache) Clear(context.Context) error             { return errBroken }
+
+func TestStoreFallsBackWhenCacheFails(t *testing.T) {
+	ctx := context.Background()
+	db := memstore.New()
+	s := NewStore(db, brokenCache{}, time.Minute)
+
+	walt, _ := db.CreateUser(ctx, database.CreateUserParams{Email: "walt@breakingbad.com"})
+	if user, err := s.GetUserByID(ctx, walt.ID); err != nil || user.ID != walt.ID {
+		t.Errorf("GetUserByID() = %v, %v, want the user from the database", user.ID, err)
+	}
+	if _, err := s.BanUser(ctx, walt.ID); err != nil {
+		t.Errorf("BanUser() error = %v, want cache errors to be ignored", err)
+	}
+}
+
+func TestStoreFreshReadsSkipTheCache(t *testing.T) {
+	ctx := context.Background()
+	db := memstore.New()
+	s := NewStore(db, NewLRU(10), time.Minute)
+
+	walt, _ := db.CreateUser(ctx, database.CreateUserParams{Email: "walt@breakingbad.com"})
+	if _, err := s.GetUserByID(ctx, walt.ID); err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+
+	// Otra réplica banea al usuario: este caché no se entera
+	if _, err := db.BanUser(ctx, walt.ID); err != nil {
+		t.Fatalf("BanUser() error = %v", err)
+	}
+	if user, _ := s.GetUserByID(service.FreshReads(ctx), walt.ID); !user.BannedAt.Valid {
+		t.Error("GetUserByID() with FreshReads served the cached user")
+	}
+	// La lectura fresca también renueva la entrada para las demás lecturas
+	if user, _ := s.GetUserByID(ctx, walt.ID); !user.BannedAt.Valid {
+		t.Error("GetUserByID() after a fresh read served the old user")
+	}
+}
+
+// racingStore ejecuta during una vez, justo después de leer un usuario de la base de datos.
+type racingStore struct {
+	*memstore.Store
+	during func()
+}
+
+func (s *racingStore) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, err := s.Store.GetUserByID(ctx, id)
+	if s.during != nil {
+		during := s.during
+		s.during = nil
+		during()
+	}
+	return user, err
+}
+
+func TestStoreDoesNotCacheRowsReadBeforeAnInvalidation(t *testing.T) {
+	ctx := context.Background()
+	db := &racingStore{Store: memstore.New()}
+	s := NewStore(db, NewLRU(10), time.Minute)
+
+	walt, _ := db.CreateUser(ctx, database.CreateUserParams{Email: "walt@breakingbad.com"})
+
+	// El baneo se guarda e invalida entre la lectura del usuario y su Set en el caché
+	db.during = func() {
+		if _, err := s.BanUser(ctx, walt.ID); err != nil {
+			t.Fatalf("BanUser() error = %v", err)
+		}
+	}
+	if user, _ := s.GetUserByID(ctx, walt.ID); user.BannedAt.Valid {
+		t.Fatal("the first read should see the user before the ban")
+	}
+	if user, _ := s.GetUserByID(ctx, walt.ID); !user.BannedAt.Valid {
+		t.Error("GetUserByID() served the row read before the ban")
+	}
+}
@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+)
+
+// Store envuelve un service.Store y sirve GetChirp y GetUserByID desde un Cache.
+// Cada escritura que cambia un chirp o un usuario borra su clave; las que borran en
+// cascada (Reset, PurgeDeletedUsers) vacían el caché entero. Dentro de InTx las
+// lecturas van siempre a la base de datos y las claves se borran después del commit.
+// Fuera de InTx también van a la base de datos si ctx viene de service.FreshReads.
+//
+// Un fallo del caché nunca hace fallar la consulta: se registra y se usa la base de datos.
+type Store struct {
+	invalidating
+	store service.Store
+	cache Cache
+	ttl   time.Duration
+
+	// fill protege generation y ordena las invalidaciones con los Set de readThrough.
+	fill sync.Mutex
+	// generation aumenta con cada invalidación; readThrough no guarda lo que leyó si
+	// cambió mientras leía, porque la fila puede ser anterior a la escritura.
+	generation uint64
+}
+
+// NewStore crea un Store que guarda los resultados en cache durante ttl.
+func NewStore(store service.Store, cache Cache, ttl time.Duration) *Store {
+	s := &Store{store: store, cache: cache, ttl: ttl}
+	s.invalidating = invalidating{Queries: store, delete: s.delete, clear: s.clear}
+	return s
+}
+
+func chirpKey(id uuid.UUID) string { return "chirp:" + id.String() }
+func userKey(id uuid.UUID) string  { return "user:" + id.String() }
+
+// Entidades para la etiqueta de las métricas
+const (
+	entityChirp = "chirp"
+	entityUser  = "user"
+)
+
+func (s *Store) GetChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+	return readThrough(ctx, s, entityChirp, chirpKey(id), func() (database.Chirp, error) {
+		return s.store.GetChirp(ctx, id)
+	})
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return readThrough(ctx, s, entityUser, userKey(id), func() (database.User, error) {
+		return s.store.GetUserByID(ctx, id)
+	})
+}
+
+// InTx ejecuta fn con consultas que anotan las claves a invalidar y las borra al terminar,
+// haya commit o rollback.
+func (s *Store) InTx(ctx context.Context, fn func(q service.Queries) error) error {
+	var mu sync.Mutex
+	var keys []string
+	clearAll := false
+
+	err := s.store.InTx(ctx, func(q service.Queries) error {
+		return fn(invalidating{
+			Queries: q,
+			delete: func(_ context.Context, k ...string) {
+				mu.Lock()
+				keys = append(keys, k...)
+				mu.Unlock()
+			},
+			clear: func(context.Context) {
+				mu.Lock()
+				clearAll = true
+				mu.Unlock()
+			},
+		})
+	})
+
+	// El contexto de la petición puede estar cancelado: la invalidación debe hacerse igual
+	ctx = context.WithoutCancel(ctx)
+	if clearAll {
+		s.clear(ctx)
+	} else if len(keys) > 0 {
+		s.delete(ctx, keys...)
+	}
+	return err
+}
+
+// readThrough devuelve el valor de key si está en el caché y, si no, lo lee con load y lo guarda.
+// Los errores (incluido "no existe") no se guardan.
+// Los errores (incluido "no existe") no se guardan. Con service.FreshReads no se consulta
+// el caché, pero lo leído se guarda igual para que las demás lecturas lo aprovechen.
+func readThrough[T any](ctx context.Context, s *Store, entity, key string, load func() (T, error)) (T, error) {
+	result := metrics.CacheBypass
+	if !service.WantsFreshReads(ctx) {
+		if data, ok, err := s.cache.Get(ctx, key); err != nil {
+			slog.WarnContext(ctx, "cache get failed", "key", key, "error", err)
+		} else if ok {
+			var value T
+			if err := json.Unmarshal(data, &value); err == nil {
+				metrics.CacheRequests.WithLabelValues(entity, metrics.CacheHit).Inc()
+				return value, nil
+			}
+			slog.WarnContext(ctx, "cache entry is corrupt", "key", key, "error", err)
+		}
+		result = metrics.CacheMiss
+	}
+	metrics.CacheRequests.WithLabelValues(entity, result).Inc()
+
+	s.fill.Lock()
+	generation := s.generation
+	s.fill.Unlock()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		s.fill.Lock()
+		if s.generation == generation {
+			err = s.cache.Set(ctx, key, data, s.ttl)
+		}
+		s.fill.Unlock()
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "cache set failed", "key", key, "error", err)
+	}
+	return value, nil
+}
+
+func (s *Store) delete(ctx context.Context, keys ...string) {
+	s.fill.Lock()
+	defer s.fill.Unlock()
+
+	s.generation++
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		slog.ErrorContext(ctx, "cache invalidation failed; entries stay stale until they expire", "keys", keys, "error", err)
+	}
+}
+
+func (s *Store) clear(ctx context.Context) {
+	s.fill.Lock()
+	defer s.fill.Unlock()
+
+	s.generation++
+	if err := s.cache.Clear(ctx); err != nil {
+		slog.ErrorContext(ctx, "cache clear failed; entries stay stale until they expire", "error", err)
+	}
+}
+
+// invalidating envuelve consultas y, tras cada escritura que tiene éxito, invalida las
+// claves que deja obsoletas.
+type invalidating struct {
+	service.Queries
+	delete func(ctx context.Context, keys ...string)
+	clear  func(ctx context.Context)
+}
+
+// user invalida al usuario id si la escritura tuvo éxito.
+func (q invalidating) user(ctx context.Context, id uuid.UUID, user database.User, err error) (database.User, error) {
+	if err == nil {
+		q.delete(ctx, userKey(id))
+	}
+	return user, err
+}
+
+func (q invalidating) UpdateUser(ctx context.Context, arg database.UpdateUserParams) (database.User, error) {
+	user, err := q.Queries.UpdateUser(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) UpdateUserEmail(ctx context.Context, arg database.UpdateUserEmailParams) (database.User, error) {
+	user, err := q.Queries.UpdateUserEmail(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
+	user, err := q.Queries.UpdateUserProfile(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) PatchUser(ctx context.Context, arg database.PatchUserParams) (database.User, error) {
+	user, err := q.Queries.PatchUser(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) UpgradeToChirpyRed(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, err := q.Queries.UpgradeToChirpyRed(ctx, id)
+	return q.user(ctx, id, user, err)
+}
+
+func (q invalidating) SetChirpyRed(ctx context.Context, arg database.SetChirpyRedParams) (database.User, error) {
+	user, err := q.Queries.SetChirpyRed(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (database.User, error) {
+	user, err := q.Queries.SetUserRole(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) SuspendUser(ctx context.Context, arg database.SuspendUserParams) (database.User, error) {
+	user, err := q.Queries.SuspendUser(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) BanUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, err := q.Queries.BanUser(ctx, id)
+	return q.user(ctx, id, user, err)
+}
+
+func (q invalidating) ReinstateUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, err := q.Queries.ReinstateUser(ctx, id)
+	return q.user(ctx, id, user, err)
+}
+
+func (q invalidating) SoftDeleteUser(ctx context.Context, arg database.SoftDeleteUserParams) (database.User, error) {
+	user, err := q.Queries.SoftDeleteUser(ctx, arg)
+	return q.user(ctx, arg.ID, user, err)
+}
+
+func (q invalidating) CancelUserDeletion(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, err := q.Queries.CancelUserDeletion(ctx, id)
+	return q.user(ctx, id, user, err)
+}
+
+func (q invalidating) HideChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+	chirp, err := q.Queries.HideChirp(ctx, id)
+	if err == nil {
+		q.delete(ctx, chirpKey(id))
+	}
+	return chirp, err
+}
+
+func (q invalidating) DeleteChirp(ctx context.Context, id uuid.UUID) error {
+	err := q.Queries.DeleteChirp(ctx, id)
+	if err == nil {
+		q.delete(ctx, chirpKey(id))
+	}
+	return err
+}
+
+// Reset y PurgeDeletedUsers borran usuarios y, en cascada, sus chirps: se vacía todo.
+
+func (q invalidating) Reset(ctx context.Context) error {
+	err := q.Queries.Reset(ctx)
+	if err == nil {
+		q.clear(ctx)
+	}
+	return err
+}
+
+func (q invalidating) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	purged, err := q.Queries.PurgeDeletedUsers(ctx)
+	if err == nil && purged > 0 {
+		q.clear(ctx)
+	}
+	return purged, err
+}
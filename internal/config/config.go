@@ -0,0 +1,263 @@
+// Package config carga la configuración de Chirpy.
+//
+// El orden de precedencia es: valores por defecto, archivo YAML opcional
+// (-config o CHIRPY_CONFIG), variables de entorno (también desde un .env opcional)
+// y, por último, flags de línea de comandos.
+//
+// Cada campo declara su clave YAML, su variable de entorno y si es un secreto.
+// El nombre del flag es la ruta YAML con puntos, por ejemplo -server.addr o -auth.access_token_ttl.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Config es la configuración completa de la aplicación.
+type Config struct {
+	Platform      string              `yaml:"platform" env:"PLATFORM" help:"platform name; \"dev\" enables POST /admin/reset"`
+	LogLevel      string              `yaml:"log_level" env:"LOG_LEVEL" help:"log level: debug, info, warn or error"`
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Auth          AuthConfig          `yaml:"auth"`
+	Chirps        ChirpsConfig        `yaml:"chirps"`
+	Accounts      AccountsConfig      `yaml:"accounts"`
+	Cache         CacheConfig         `yaml:"cache"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Idempotency   IdempotencyConfig   `yaml:"idempotency"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Mail          MailConfig          `yaml:"mail"`
+	Metrics       MetricsConfig       `yaml:"metrics"`
+	Features      FeatureConfig       `yaml:"features"`
+}
+
+// ServerConfig agrupa los límites del http.Server y los tiempos del apagado.
+type ServerConfig struct {
+	Addr              string        `yaml:"addr" env:"LISTEN_ADDR" help:"address the API listens on"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" help:"maximum time to read a whole request"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" help:"maximum time to read request headers"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" help:"maximum time to write a response"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" help:"how long keep-alive connections stay open"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES" help:"maximum size of request headers"`
+	// DrainDelay es el tiempo que /readyz falla antes de dejar de aceptar conexiones.
+	DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" help:"how long /readyz fails before the server stops accepting connections"`
+	// ShutdownTimeout es el máximo que se espera a las peticiones en curso.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" help:"how long to wait for in-flight requests on shutdown"`
+}
+
+// DatabaseConfig configura la conexión a la base de datos y el pool de conexiones.
+type DatabaseConfig struct {
+	URL             string        `yaml:"url" env:"DB_URL" secret:"true" help:"Postgres connection URL, or sqlite://path/to/file.db for local development"`
+	AutoMigrate     bool          `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE" help:"apply pending migrations at startup"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" help:"maximum open connections (0 = unlimited)"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" help:"maximum idle connections"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" help:"maximum lifetime of a connection (0 = forever)"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" help:"maximum idle time of a connection (0 = forever)"`
+}
+
+// AuthConfig contiene los secretos y la duración de los tokens.
+type AuthConfig struct {
+	JWTSecret            string        `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true" help:"secret used to sign access tokens"`
+	PolkaKey             string        `yaml:"polka_key" env:"POLKA_KEY" secret:"true" help:"API key expected on Polka webhooks"`
+	AccessTokenTTL       time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL" help:"lifetime of access tokens (JWT)"`
+	RefreshTokenTTL      time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL" help:"lifetime of refresh tokens"`
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl" env:"EMAIL_VERIFICATION_TTL" help:"lifetime of email verification tokens"`
+}
+
+// ChirpsConfig contiene los límites de los chirps.
+type ChirpsConfig struct {
+	MaxLength int `yaml:"max_length" env:"CHIRP_MAX_LENGTH" help:"maximum chirp length in bytes"`
+}
+
+// AccountsConfig controla la eliminación de cuentas.
+type AccountsConfig struct {
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period" env:"ACCOUNT_DELETION_GRACE_PERIOD" help:"time a deleted account can still be restored by logging in"`
+	PurgeInterval       time.Duration `yaml:"purge_interval" env:"ACCOUNT_PURGE_INTERVAL" help:"how often deleted accounts past their grace period are purged"`
+}
+
+// CacheConfig dimensiona el caché de lectura de chirps y usuarios (features.cache lo activa).
+type CacheConfig struct {
+	Size int           `yaml:"size" env:"CACHE_SIZE" help:"maximum number of cached chirps and users"`
+	TTL  time.Duration `yaml:"ttl" env:"CACHE_TTL" help:"how long a cached chirp or user is served before reading it again"`
+}
+
+// RateLimitConfig fija cuántas peticiones por minuto admite cada política de rate
+// limiting (features.rate_limit lo activa). Las rutas sin política propia usan Default.
+type RateLimitConfig struct {
+	Signup        int `yaml:"signup" env:"RATE_LIMIT_SIGNUP" help:"sign-ups per minute per IP"`
+	Login         int `yaml:"login" env:"RATE_LIMIT_LOGIN" help:"login attempts per minute per IP"`
+	Chirps        int `yaml:"chirps" env:"RATE_LIMIT_CHIRPS" help:"chirps created per minute per user"`
+	Webhooks      int `yaml:"webhooks" env:"RATE_LIMIT_WEBHOOKS" help:"webhook calls per minute per IP"`
+	Default       int `yaml:"default" env:"RATE_LIMIT_DEFAULT" help:"requests per minute per user or IP on the other routes"`
+	RedMultiplier int `yaml:"red_multiplier" env:"RATE_LIMIT_RED_MULTIPLIER" help:"quota multiplier for Chirpy Red users"`
+}
+
+// IdempotencyConfig controla cuánto se guardan las respuestas de las peticiones con
+// header Idempotency-Key.
+type IdempotencyConfig struct {
+	KeyTTL time.Duration `yaml:"key_ttl" env:"IDEMPOTENCY_KEY_TTL" help:"how long a response is replayed for retries with the same Idempotency-Key"`
+}
+
+// NotificationsConfig controla cómo se agrupan las notificaciones.
+type NotificationsConfig struct {
+	CollapseWindow time.Duration `yaml:"collapse_window" env:"NOTIFICATIONS_COLLAPSE_WINDOW" help:"how long unread notifications of the same kind keep collapsing into one (0 disables it)"`
+}
+
+// MailConfig configura el envío de emails. Sin servidor SMTP, los emails se escriben en
+// stderr, lo que solo sirve para desarrollo.
+type MailConfig struct {
+	SMTPAddr     string `yaml:"smtp_addr" env:"SMTP_ADDR" help:"host:port of the SMTP server (empty = write emails to stderr)"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME" help:"SMTP username (empty = no authentication)"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD" secret:"true" help:"SMTP password"`
+	From         string `yaml:"from" env:"MAIL_FROM" help:"sender address of outgoing emails"`
+}
+
+// MetricsConfig controla dónde se exponen las métricas.
+type MetricsConfig struct {
+	// Addr, si no está vacío, sirve /metrics en un listener aparte;
+	// si está vacío, las métricas quedan en GET /admin/metrics solo para administradores.
+	Addr string `yaml:"addr" env:"METRICS_ADDR" help:"separate listen address for /metrics (empty = admin-only route)"`
+}
+
+// FeatureConfig permite activar o desactivar funcionalidades.
+type FeatureConfig struct {
+	Signups       bool `yaml:"signups" env:"FEATURE_SIGNUPS" help:"allow new users to sign up"`
+	Metrics       bool `yaml:"metrics" env:"FEATURE_METRICS" help:"expose Prometheus metrics"`
+	AccountPurger bool `yaml:"account_purger" env:"FEATURE_ACCOUNT_PURGER" help:"run the background purge of deleted accounts"`
+	Cache         bool `yaml:"cache" env:"FEATURE_CACHE" help:"serve chirp and user lookups from an in-process cache"`
+	RateLimit     bool `yaml:"rate_limit" env:"FEATURE_RATE_LIMIT" help:"limit requests per user or IP"`
+}
+
+// Default devuelve la configuración por defecto. Los secretos no tienen valor por defecto.
+func Default() Config {
+	return Config{
+		LogLevel: "info",
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			DrainDelay:        5 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:       time.Hour,
+			RefreshTokenTTL:      60 * 24 * time.Hour,
+			EmailVerificationTTL: 24 * time.Hour,
+		},
+		Chirps: ChirpsConfig{
+			MaxLength: 140,
+		},
+		Accounts: AccountsConfig{
+			DeletionGracePeriod: 30 * 24 * time.Hour,
+			PurgeInterval:       time.Hour,
+		},
+		Cache: CacheConfig{
+			Size: 10000,
+			TTL:  time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			Signup:        5,
+			Login:         10,
+			Chirps:        30,
+			Webhooks:      120,
+			Default:       300,
+			RedMultiplier: 5,
+		},
+		Idempotency: IdempotencyConfig{
+			KeyTTL: 24 * time.Hour,
+		},
+		Notifications: NotificationsConfig{
+			CollapseWindow: time.Hour,
+		},
+		Features: FeatureConfig{
+			Signups:       true,
+			Metrics:       true,
+			AccountPurger: true,
+			Cache:         true,
+			RateLimit:     true,
+		},
+	}
+}
+
+// Validate comprueba toda la configuración y devuelve todos los problemas juntos.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Database.URL != "", "database.url (DB_URL) is required")
+	check(c.Auth.JWTSecret != "", "auth.jwt_secret (JWT_SECRET) is required")
+	check(c.Auth.PolkaKey != "", "auth.polka_key (POLKA_KEY) is required")
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "log_level must be debug, info, warn or error, got %q", c.LogLevel)
+	}
+
+	check(c.Server.Addr != "", "server.addr is required")
+	check(c.Server.ReadTimeout > 0, "server.read_timeout must be positive")
+	check(c.Server.ReadHeaderTimeout > 0, "server.read_header_timeout must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout must be positive")
+	check(c.Server.MaxHeaderBytes > 0, "server.max_header_bytes must be positive")
+	check(c.Server.DrainDelay >= 0, "server.drain_delay can't be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns can't be negative")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns can't be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns (%d) can't exceed database.max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime can't be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time can't be negative")
+
+	check(c.Auth.AccessTokenTTL > 0, "auth.access_token_ttl must be positive")
+	check(c.Auth.RefreshTokenTTL > c.Auth.AccessTokenTTL, "auth.refresh_token_ttl must be longer than auth.access_token_ttl")
+	check(c.Auth.EmailVerificationTTL > 0, "auth.email_verification_ttl must be positive")
+
+	check(c.Chirps.MaxLength > 0 && c.Chirps.MaxLength <= 10000, "chirps.max_length must be between 1 and 10000, got %d", c.Chirps.MaxLength)
+
+	check(c.Accounts.DeletionGracePeriod >= 0, "accounts.deletion_grace_period can't be negative")
+	check(c.Accounts.PurgeInterval > 0, "accounts.purge_interval must be positive")
+
+	if c.Features.Cache {
+		check(c.Cache.Size > 0, "cache.size must be positive")
+		check(c.Cache.TTL > 0, "cache.ttl must be positive")
+	}
+
+	if c.Features.RateLimit {
+		check(c.RateLimit.Signup > 0, "rate_limit.signup must be positive")
+		check(c.RateLimit.Login > 0, "rate_limit.login must be positive")
+		check(c.RateLimit.Chirps > 0, "rate_limit.chirps must be positive")
+		check(c.RateLimit.Webhooks > 0, "rate_limit.webhooks must be positive")
+		check(c.RateLimit.Default > 0, "rate_limit.default must be positive")
+		check(c.RateLimit.RedMultiplier >= 1, "rate_limit.red_multiplier must be at least 1")
+	}
+
+	check(c.Idempotency.KeyTTL > 0, "idempotency.key_ttl must be positive")
+	check(c.Notifications.CollapseWindow >= 0, "notifications.collapse_window must not be negative")
+
+	if c.Mail.SMTPAddr != "" {
+		_, _, err := net.SplitHostPort(c.Mail.SMTPAddr)
+		check(err == nil, "mail.smtp_addr must be host:port, got %q", c.Mail.SMTPAddr)
+		check(c.Mail.From != "", "mail.from (MAIL_FROM) is required to send emails over SMTP")
+	}
+
+	check(c.Metrics.Addr == "" || c.Metrics.Addr != c.Server.Addr, "metrics.addr must differ from server.addr")
+
+	return errors.Join(errs...)
+}
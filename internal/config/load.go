@@ -0,0 +1,200 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Load lee la configuración completa a partir de los argumentos de línea de comandos
+// (sin el nombre del programa). Devuelve también los argumentos que no son flags, por ejemplo
+// un subcomando. Un archivo .env en el directorio actual es opcional.
+// La validación queda a cargo de quien llama (Validate), porque depende del subcomando.
+func Load(args []string) (Config, []string, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, nil, fmt.Errorf("couldn't load .env: %w", err)
+	}
+	return load(args, os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (Config, []string, error) {
+	defaultPath, _ := lookupEnv("CHIRPY_CONFIG")
+
+	// Primera pasada de flags solo para saber qué archivo de configuración leer
+	scratch := Default()
+	path := defaultPath
+	if err := newFlagSet(&scratch, &path, defaultPath).Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	cfg := Default()
+	if path != "" {
+		if err := loadFile(&cfg, path); err != nil {
+			return Config{}, nil, err
+		}
+	}
+	if err := loadEnv(&cfg, lookupEnv); err != nil {
+		return Config{}, nil, err
+	}
+
+	// Segunda pasada: los flags tienen la última palabra
+	flags := newFlagSet(&cfg, &path, defaultPath)
+	if err := flags.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	return cfg, flags.Args(), nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("couldn't open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("couldn't parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config, lookupEnv func(string) (string, bool)) error {
+	for _, f := range fields(cfg) {
+		raw, ok := lookupEnv(f.env)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := setValue(f.value, raw); err != nil {
+			return fmt.Errorf("invalid %s: %w", f.env, err)
+		}
+	}
+	return nil
+}
+
+func newFlagSet(cfg *Config, configPath *string, defaultPath string) *flag.FlagSet {
+	flags := flag.NewFlagSet("chirpy", flag.ContinueOnError)
+	flags.StringVar(configPath, "config", defaultPath, "path to a YAML config file (env CHIRPY_CONFIG)")
+	for _, f := range fields(cfg) {
+		flags.Var(flagValue{f.value}, f.path, fmt.Sprintf("%s (env %s)", f.help, f.env))
+	}
+	return flags
+}
+
+// Redacted devuelve la configuración efectiva como ruta → valor, con los secretos ocultos.
+func (c Config) Redacted() map[string]string {
+	out := map[string]string{}
+	for _, f := range fields(&c) {
+		value := formatValue(f.value)
+		if f.secret && value != "" {
+			value = "[REDACTED]"
+		}
+		out[f.path] = value
+	}
+	return out
+}
+
+// field es un campo configurable con su ruta YAML, su variable de entorno y su valor.
+type field struct {
+	path   string
+	env    string
+	help   string
+	secret bool
+	value  reflect.Value
+}
+
+func fields(cfg *Config) []field {
+	var out []field
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			path := prefix + sf.Tag.Get("yaml")
+			if sf.Type.Kind() == reflect.Struct {
+				walk(path+".", v.Field(i))
+				continue
+			}
+			out = append(out, field{
+				path:   path,
+				env:    sf.Tag.Get("env"),
+				help:   sf.Tag.Get("help"),
+				secret: sf.Tag.Get("secret") == "true",
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk("", reflect.ValueOf(cfg).Elem())
+	return out
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration like 10s or 1h", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// flagValue adapta un campo de Config a flag.Value.
+type flagValue struct {
+	v reflect.Value
+}
+
+func (f flagValue) String() string {
+	if !f.v.IsValid() {
+		return ""
+	}
+	return formatValue(f.v)
+}
+
+func (f flagValue) Set(raw string) error {
+	return setValue(f.v, raw)
+}
+
+// IsBoolFlag permite escribir -features.signups sin "=true".
+func (f flagValue) IsBoolFlag() bool {
+	return f.v.IsValid() && f.v.Kind() == reflect.Bool
+}
@@ -7,29 +7,32 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, reply_to_id)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, hidden_at, reply_to_id
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	Body      string
+	UserID    uuid.UUID
+	ReplyToID uuid.NullUUID
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID, arg.ReplyToID)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,6 +40,8 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.HiddenAt,
+		&i.ReplyToID,
 	)
 	return i, err
 }
@@ -52,7 +57,7 @@ func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
 }
 
 const getChirp = `-- name: GetChirp :one
-SELECT id, created_at, updated_at, body, user_id FROM chirps
+SELECT id, created_at, updated_at, body, user_id, hidden_at, reply_to_id FROM chirps
 WHERE id = $1
 `
 
@@ -65,21 +70,181 @@ func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.HiddenAt,
+		&i.ReplyToID,
+	)
+	return i, err
+}
+
+const getChirpWithAuthor = `-- name: GetChirpWithAuthor :one
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.hidden_at, chirps.reply_to_id, users.username, users.display_name, users.avatar_url FROM chirps
+JOIN users ON users.id = chirps.user_id
+WHERE chirps.id = $1 AND users.deleted_at IS NULL
+`
+
+type GetChirpWithAuthorRow struct {
+	Chirp       Chirp
+	Username    sql.NullString
+	DisplayName string
+	AvatarUrl   string
+}
+
+func (q *Queries) GetChirpWithAuthor(ctx context.Context, id uuid.UUID) (GetChirpWithAuthorRow, error) {
+	row := q.db.QueryRowContext(ctx, getChirpWithAuthor, id)
+	var i GetChirpWithAuthorRow
+	err := row.Scan(
+		&i.Chirp.ID,
+		&i.Chirp.CreatedAt,
+		&i.Chirp.UpdatedAt,
+		&i.Chirp.Body,
+		&i.Chirp.UserID,
+		&i.Chirp.HiddenAt,
+		&i.Chirp.ReplyToID,
+		&i.Username,
+		&i.DisplayName,
+		&i.AvatarUrl,
 	)
 	return i, err
 }
 
 const getChirps = `-- name: GetChirps :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-ORDER BY created_at ASC
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.hidden_at, chirps.reply_to_id, users.username, users.display_name, users.avatar_url FROM chirps
+JOIN users ON users.id = chirps.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY chirps.created_at ASC
 `
 
-func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
+type GetChirpsRow struct {
+	Chirp       Chirp
+	Username    sql.NullString
+	DisplayName string
+	AvatarUrl   string
+}
+
+func (q *Queries) GetChirps(ctx context.Context) ([]GetChirpsRow, error) {
 	rows, err := q.db.QueryContext(ctx, getChirps)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	var items []GetChirpsRow
+	for rows.Next() {
+		var i GetChirpsRow
+		if err := rows.Scan(
+			&i.Chirp.ID,
+			&i.Chirp.CreatedAt,
+			&i.Chirp.UpdatedAt,
+			&i.Chirp.Body,
+			&i.Chirp.UserID,
+			&i.Chirp.HiddenAt,
+			&i.Chirp.ReplyToID,
+			&i.Username,
+			&i.DisplayName,
+			&i.AvatarUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsForViewer = `-- name: GetChirpsForViewer :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.hidden_at, chirps.reply_to_id, users.username, users.display_name, users.avatar_url FROM chirps
+JOIN users ON users.id = chirps.user_id
+WHERE users.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $1 AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocks.blocker_id = $1 AND blocks.blocked_id = chirps.user_id)
+    OR (blocks.blocker_id = chirps.user_id AND blocks.blocked_id = $1)
+)
+ORDER BY chirps.created_at ASC
+`
+
+type GetChirpsForViewerRow struct {
+	Chirp       Chirp
+	Username    sql.NullString
+	DisplayName string
+	AvatarUrl   string
+}
+
+func (q *Queries) GetChirpsForViewer(ctx context.Context, viewerID uuid.UUID) ([]GetChirpsForViewerRow, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsForViewer, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChirpsForViewerRow
+	for rows.Next() {
+		var i GetChirpsForViewerRow
+		if err := rows.Scan(
+			&i.Chirp.ID,
+			&i.Chirp.CreatedAt,
+			&i.Chirp.UpdatedAt,
+			&i.Chirp.Body,
+			&i.Chirp.UserID,
+			&i.Chirp.HiddenAt,
+			&i.Chirp.ReplyToID,
+			&i.Username,
+			&i.DisplayName,
+			&i.AvatarUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hideChirp = `-- name: HideChirp :one
+UPDATE chirps SET hidden_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, hidden_at, reply_to_id
+`
+
+func (q *Queries) HideChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, hideChirp, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.HiddenAt,
+		&i.ReplyToID,
+	)
+	return i, err
+}
+
+const listChirpsByUser = `-- name: ListChirpsByUser :many
+SELECT id, created_at, updated_at, body, user_id, hidden_at, reply_to_id FROM chirps
+WHERE user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 	var items []Chirp
 	for rows.Next() {
 		var i Chirp
@@ -89,6 +254,8 @@ func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.HiddenAt,
+			&i.ReplyToID,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Errores de base de datos clasificados según su causa.
+// ClassifyError los envuelve junto al error original del driver.
+var (
+	ErrNotFound   = errors.New("record not found")
+	ErrConflict   = errors.New("record conflicts with an existing one")
+	ErrReference  = errors.New("referenced record does not exist")
+	ErrConstraint = errors.New("value violates a database constraint")
+)
+
+// Códigos SQLSTATE de Postgres que sabemos clasificar
+const (
+	pqNotNullViolation    = "23502"
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+	pqCheckViolation      = "23514"
+
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+)
+
+// ClassifyError envuelve err con ErrNotFound, ErrConflict, ErrReference o ErrConstraint
+// cuando reconoce la causa. Los errores desconocidos se devuelven sin cambios.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		case pqForeignKeyViolation:
+			return fmt.Errorf("%w: %w", ErrReference, err)
+		case pqCheckViolation, pqNotNullViolation:
+			return fmt.Errorf("%w: %w", ErrConstraint, err)
+		}
+	}
+
+	return err
+}
+
+// IsNotFound indica si err significa que no existe el registro buscado.
+func IsNotFound(err error) bool {
+	return errors.Is(ClassifyError(err), ErrNotFound)
+}
+
+// IsConflict indica si err es una violación de unicidad.
+func IsConflict(err error) bool {
+	return errors.Is(ClassifyError(err), ErrConflict)
+}
+
+// IsSerializationFailure indica si err abortó una transacción por un conflicto con otra
+// transacción concurrente (serialization_failure o deadlock_detected). Reintentar la
+// transacción completa es seguro.
+func IsSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "No rows",
+			err:  sql.ErrNoRows,
+			want: ErrNotFound,
+		},
+		{
+			name: "Wrapped no rows",
+			err:  fmt.Errorf("get chirp: %w", sql.ErrNoRows),
+			want: ErrNotFound,
+		},
+		{
+			name: "Unique violation",
+			err:  &pq.Error{Code: "23505"},
+			want: ErrConflict,
+		},
+		{
+			name: "Foreign key violation",
+			err:  &pq.Error{Code: "23503"},
+			want: ErrReference,
+		},
+		{
+			name: "Check violation",
+			err:  &pq.Error{Code: "23514"},
+			want: ErrConstraint,
+		},
+		{
+			name: "Unknown error",
+			err:  errors.New("connection refused"),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("ClassifyError() = %v, should still wrap %v", got, tt.err)
+			}
+			for _, known := range []error{ErrNotFound, ErrConflict, ErrReference, ErrConstraint} {
+				if errors.Is(got, known) != (known == tt.want) {
+					t.Errorf("ClassifyError() = %v, errors.Is(%v) = %v", got, known, errors.Is(got, known))
+				}
+			}
+		})
+	}
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&pq.Error{Code: "40001"}, true},
+		{fmt.Errorf("commit: %w", &pq.Error{Code: "40P01"}), true},
+		{&pq.Error{Code: "23505"}, false},
+		{sql.ErrNoRows, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsSerializationFailure(tt.err); got != tt.want {
+			t.Errorf("IsSerializationFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -11,12 +11,94 @@ import (
 	"github.com/google/uuid"
 )
 
+type AdminAuditLog struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ActorID    uuid.NullUUID
+	Action     string
+	TargetType string
+	TargetID   uuid.UUID
+	Details    string
+}
+
+type Block struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	CreatedAt time.Time
+}
+
 type Chirp struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Body      string
 	UserID    uuid.UUID
+	HiddenAt  sql.NullTime
+	ReplyToID uuid.NullUUID
+}
+
+type EmailVerification struct {
+	Token     string
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FollowedID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type IdempotencyKey struct {
+	Scope        string
+	Key          string
+	Fingerprint  string
+	StatusCode   int32
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+type Like struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Mute struct {
+	MuterID   uuid.UUID
+	MutedID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Notification struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Type       string
+	GroupKey   string
+	ChirpID    uuid.NullUUID
+	ActorID    uuid.NullUUID
+	ActorCount int32
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ReadAt     sql.NullTime
+}
+
+type NotificationActor struct {
+	NotificationID uuid.UUID
+	ActorID        uuid.UUID
+	CreatedAt      time.Time
+}
+
+type NotificationPreference struct {
+	UserID    uuid.UUID
+	Type      string
+	Enabled   bool
+	UpdatedAt time.Time
 }
 
 type RefreshToken struct {
@@ -28,6 +110,29 @@ type RefreshToken struct {
 	RevokedAt sql.NullTime
 }
 
+type Report struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ReporterID     uuid.UUID
+	ReportedUserID uuid.UUID
+	ChirpID        uuid.NullUUID
+	Reason         string
+	Details        string
+	Status         string
+	Resolution     sql.NullString
+	ResolvedBy     uuid.NullUUID
+	ResolvedAt     sql.NullTime
+}
+
+type SubscriptionEvent struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	Event     string
+	Source    string
+}
+
 type User struct {
 	ID             uuid.UUID
 	CreatedAt      time.Time
@@ -35,4 +140,13 @@ type User struct {
 	Email          string
 	HashedPassword string
 	IsChirpyRed    bool
+	Role           string
+	SuspendedUntil sql.NullTime
+	BannedAt       sql.NullTime
+	Username       sql.NullString
+	DisplayName    string
+	Bio            string
+	AvatarUrl      string
+	DeletedAt      sql.NullTime
+	PurgeAfter     sql.NullTime
 }
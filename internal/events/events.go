@@ -0,0 +1,240 @@
+// Package events reparte en tiempo real los cambios de los chirps y las notificaciones
+// entre los clientes conectados (GET /api/stream/chirps y /api/ws).
+//
+// Broker entrega los eventos a los suscriptores de este proceso y guarda los últimos
+// para que un cliente que se reconecta con Last-Event-ID recupere los que se perdió.
+// Con varias réplicas, un Fanout (PostgresFanout) hace que cada evento llegue a todas.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// Tipos de evento
+const (
+	// ChirpCreated lleva el chirp publicado.
+	ChirpCreated = "chirp.created"
+	// ChirpDeleted indica que un chirp dejó de ser público: lo borró su autor o un
+	// moderador, o se ocultó al resolver un reporte.
+	ChirpDeleted = "chirp.deleted"
+	// NotificationCreated lleva una notificación nueva o actualizada para RecipientID.
+	NotificationCreated = "notification.created"
+)
+
+// Event es un cambio en un chirp o una notificación. Chirp solo viene en ChirpCreated; si
+// no cabía en el transporte entre réplicas, quien lo recibe lo lee de la base de datos
+// antes de entregarlo, así que los suscriptores siempre lo tienen. Hashtags son los del cuerpo del chirp, también en ChirpDeleted, para que
+// quien sigue un hashtag se entere de que el chirp ya no está. Notification solo viene
+// en NotificationCreated.
+type Event struct {
+	ID           string                 `json:"id"`
+	Type         string                 `json:"type"`
+	ChirpID      uuid.UUID              `json:"chirp_id"`
+	AuthorID     uuid.UUID              `json:"author_id"`
+	Hashtags     []string               `json:"hashtags,omitempty"`
+	Chirp        *database.Chirp        `json:"chirp,omitempty"`
+	RecipientID  uuid.UUID              `json:"recipient_id"`
+	Notification *database.Notification `json:"notification,omitempty"`
+}
+
+// IsChirpEvent indica si e es un cambio en un chirp.
+func (e Event) IsChirpEvent() bool {
+	return e.Type == ChirpCreated || e.Type == ChirpDeleted
+}
+
+// Fanout lleva los eventos publicados en cualquier réplica al Broker de todas ellas.
+type Fanout interface {
+	// Send envía e a todas las réplicas, incluida esta; cada una lo entrega con Deliver.
+	Send(ctx context.Context, e Event) error
+}
+
+// Errores con los que termina una suscripción
+var (
+	ErrSlowConsumer = errors.New("subscriber fell too far behind")
+	ErrClosed       = errors.New("event broker is closed")
+)
+
+// Broker reparte los eventos entre los suscriptores de este proceso.
+type Broker struct {
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	history []Event // los últimos eventos, del más antiguo al más reciente
+	closed  bool
+
+	historySize int
+	bufferSize  int
+	fanout      Fanout
+	instance    string
+}
+
+// NewBroker crea un Broker que recuerda los últimos historySize eventos y deja que
+// cada suscriptor acumule hasta bufferSize sin leer antes de desconectarlo.
+func NewBroker(historySize, bufferSize int) *Broker {
+	instance := make([]byte, 4)
+	rand.Read(instance)
+	return &Broker{
+		subs:        make(map[*Subscription]struct{}),
+		history:     make([]Event, 0, historySize),
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		instance:    hex.EncodeToString(instance),
+	}
+}
+
+// SetFanout hace que los eventos publicados pasen por f antes de entregarse.
+// Debe llamarse antes de publicar.
+func (b *Broker) SetFanout(f Fanout) {
+	b.fanout = f
+}
+
+// Publish asigna un ID a e y lo entrega, a través del Fanout si lo hay.
+func (b *Broker) Publish(ctx context.Context, e Event) error {
+	e.ID = b.newID()
+	if b.fanout != nil {
+		return b.fanout.Send(ctx, e)
+	}
+	b.Deliver(e)
+	return nil
+}
+
+// newID genera IDs que empiezan por el instante de publicación en microsegundos, para
+// poder reanudar por tiempo cuando el ID no está en el historial de esta réplica.
+func (b *Broker) newID() string {
+	return strconv.FormatInt(time.Now().UnixMicro(), 10) + "-" + b.instance + "-" + uuid.NewString()[:8]
+}
+
+// Deliver guarda e en el historial y lo entrega a los suscriptores a los que les interesa.
+// Los que tienen el buffer lleno se desconectan con ErrSlowConsumer: es preferible que se
+// reconecten y recuperen lo perdido a frenar a todos los demás.
+func (b *Broker) Deliver(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	if len(b.history) == b.historySize {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:len(b.history)-1]
+	}
+	b.history = append(b.history, e)
+
+	for sub := range b.subs {
+		if !sub.filter(e) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			b.drop(sub, ErrSlowConsumer)
+		}
+	}
+}
+
+// Subscribe crea una suscripción a los eventos que cumplen filter. Si lastEventID no está
+// vacío, devuelve también los eventos posteriores que siguen en el historial.
+func (b *Broker) Subscribe(lastEventID string, filter func(Event) bool) (*Subscription, []Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, ErrClosed
+	}
+
+	sub := &Subscription{events: make(chan Event, b.bufferSize), filter: filter}
+	b.subs[sub] = struct{}{}
+
+	var missed []Event
+	for _, e := range b.since(lastEventID) {
+		if filter(e) {
+			missed = append(missed, e)
+		}
+	}
+	return sub, missed, nil
+}
+
+// since devuelve los eventos del historial posteriores a lastEventID. Si el ID no está
+// (es de otra réplica o ya salió del historial), usa el instante que lleva dentro.
+// Debe llamarse con el mutex tomado.
+func (b *Broker) since(lastEventID string) []Event {
+	if lastEventID == "" {
+		return nil
+	}
+	for i, e := range b.history {
+		if e.ID == lastEventID {
+			return b.history[i+1:]
+		}
+	}
+
+	after, ok := idTime(lastEventID)
+	if !ok {
+		return nil
+	}
+	for i, e := range b.history {
+		if t, _ := idTime(e.ID); t > after {
+			return b.history[i:]
+		}
+	}
+	return nil
+}
+
+func idTime(id string) (int64, bool) {
+	micros, _, _ := strings.Cut(id, "-")
+	t, err := strconv.ParseInt(micros, 10, 64)
+	return t, err == nil
+}
+
+// Unsubscribe termina sub. Se puede llamar más de una vez.
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(sub, nil)
+}
+
+// Close termina todas las suscripciones con ErrClosed y rechaza las nuevas, para que
+// los streams abiertos no retrasen el apagado del servidor.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub, ErrClosed)
+	}
+}
+
+// drop debe llamarse con el mutex tomado.
+func (b *Broker) drop(sub *Subscription, err error) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	sub.err = err
+	close(sub.events)
+}
+
+// Subscription recibe los eventos de un Broker.
+type Subscription struct {
+	events chan Event
+	filter func(Event) bool
+	err    error
+}
+
+// Events devuelve el canal de eventos. Se cierra cuando la suscripción termina.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err indica por qué terminó la suscripción. Solo es válido después de que Events se cierre.
+func (s *Subscription) Err() error {
+	return s.err
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+func all(Event) bool { return true }
+
+func publish(t *testing.T, b *Broker, author uuid.UUID) Event {
+	t.Helper()
+	e := Event{Type: ChirpCreated, ChirpID: uuid.New(), AuthorID: author}
+	if err := b.Publish(context.Background(), e); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	return b.history[len(b.history)-1]
+}
+
+func TestBrokerDeliversMatchingEvents(t *testing.T) {
+	b := NewBroker(10, 10)
+	walt, jesse := uuid.New(), uuid.New()
+
+	sub, _, err := b.Subscribe("", func(e Event) bool { return e.AuthorID == walt })
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	publish(t, b, jesse)
+	want := publish(t, b, walt)
+
+	if got := <-sub.Events(); got.ID != want.ID {
+		t.Fatalf("got event %s, want %s", got.ID, want.ID)
+	}
+	if len(sub.Events()) != 0 {
+		t.Errorf("subscriber got events from other authors")
+	}
+
+	b.Unsubscribe(sub)
+	b.Unsubscribe(sub)
+	if _, ok := <-sub.Events(); ok {
+		t.Errorf("events channel still open after Unsubscribe")
+	}
+}
+
+func TestBrokerResumesFromHistory(t *testing.T) {
+	b := NewBroker(3, 10)
+	author := uuid.New()
+	first := publish(t, b, author)
+	second := publish(t, b, author)
+	third := publish(t, b, author)
+
+	_, missed, _ := b.Subscribe(first.ID, all)
+	if len(missed) != 2 || missed[0].ID != second.ID || missed[1].ID != third.ID {
+		t.Fatalf("missed after %s = %v, want the 2 later events", first.ID, missed)
+	}
+
+	// Un ID que no está en el historial se resuelve por su instante
+	_, missed, _ = b.Subscribe("0-other-replica", all)
+	if len(missed) != 3 {
+		t.Errorf("missed after an old foreign ID = %d events, want 3", len(missed))
+	}
+
+	// El historial descarta los eventos más antiguos
+	publish(t, b, author)
+	if b.history[0].ID != second.ID {
+		t.Errorf("oldest event = %s, want %s", b.history[0].ID, second.ID)
+	}
+}
+
+func TestBrokerDropsSlowConsumers(t *testing.T) {
+	b := NewBroker(10, 1)
+	slow, _, _ := b.Subscribe("", all)
+	publish(t, b, uuid.New())
+	publish(t, b, uuid.New())
+
+	<-slow.Events()
+	if _, ok := <-slow.Events(); ok || slow.Err() != ErrSlowConsumer {
+		t.Fatalf("slow consumer: open %v, err %v, want it closed with ErrSlowConsumer", ok, slow.Err())
+	}
+
+	b.Close()
+	if _, _, err := b.Subscribe("", all); err != ErrClosed {
+		t.Errorf("Subscribe after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestPostgresFanoutLoadsMissingChirpsOnce(t *testing.T) {
+	stored := database.Chirp{ID: uuid.New(), Body: "Say my name"}
+	loads := 0
+	f := NewPostgresFanout(nil, "", NewBroker(10, 10), func(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+		loads++
+		if id != stored.ID {
+			return database.Chirp{}, sql.ErrNoRows
+		}
+		return stored, nil
+	})
+
+	e := Event{Type: ChirpCreated, ChirpID: stored.ID}
+	if !f.complete(context.Background(), &e) || e.Chirp == nil || e.Chirp.Body != stored.Body {
+		t.Fatalf("complete() left chirp %+v, want the stored one", e.Chirp)
+	}
+	// Ya tiene el chirp: no se vuelve a leer
+	if !f.complete(context.Background(), &e) || loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+
+	deleted := Event{Type: ChirpCreated, ChirpID: uuid.New()}
+	if f.complete(context.Background(), &deleted) {
+		t.Error("complete() of a deleted chirp = true, want the event dropped")
+	}
+	other := Event{Type: ChirpDeleted, ChirpID: uuid.New()}
+	if !f.complete(context.Background(), &other) || loads != 2 {
+		t.Errorf("complete() of chirp.deleted loaded the chirp (loads = %d)", loads)
+	}
+}
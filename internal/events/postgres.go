@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// postgresChannel es el canal de LISTEN/NOTIFY por el que viajan los eventos.
+const postgresChannel = "chirpy_events"
+
+// maxNotifyPayload es el tamaño máximo del payload de NOTIFY en Postgres (8000 bytes).
+const maxNotifyPayload = 7999
+
+// loadTimeout limita la lectura de un chirp que llegó sin cuerpo.
+const loadTimeout = 5 * time.Second
+
+// ChirpLoader lee un chirp de la base de datos.
+type ChirpLoader func(ctx context.Context, id uuid.UUID) (database.Chirp, error)
+
+// PostgresFanout reparte los eventos entre réplicas con LISTEN/NOTIFY: Send hace NOTIFY
+// y Run escucha el canal y entrega cada evento al Broker local.
+//
+// NOTIFY no guarda nada: si una réplica pierde la conexión de escucha, los eventos de
+// ese intervalo no le llegan, y sus clientes solo los recuperan si se reconectan a otra.
+type PostgresFanout struct {
+	db        *sql.DB
+	dbURL     string
+	broker    *Broker
+	loadChirp ChirpLoader
+}
+
+// NewPostgresFanout crea un PostgresFanout que publica con db y escucha con una conexión
+// propia a dbURL. Los chirps que llegan sin cuerpo se leen con loadChirp. Hay que llamar
+// a Run para recibir los eventos.
+func NewPostgresFanout(db *sql.DB, dbURL string, broker *Broker, loadChirp ChirpLoader) *PostgresFanout {
+	return &PostgresFanout{db: db, dbURL: dbURL, broker: broker, loadChirp: loadChirp}
+}
+
+func (f *PostgresFanout) Send(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		// Los chirps muy largos viajan sin cuerpo; quien los recibe los lee de la base de datos
+		e.Chirp = nil
+		if payload, err = json.Marshal(e); err != nil {
+			return err
+		}
+	}
+	_, err = f.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", postgresChannel, string(payload))
+	return err
+}
+
+// Run escucha el canal hasta que ctx se cancela. pq.Listener se reconecta solo.
+func (f *PostgresFanout) Run(ctx context.Context) error {
+	listener := pq.NewListener(f.dbURL, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			slog.Warn("event listener disconnected", "error", err)
+		case pq.ListenerEventReconnected:
+			slog.Info("event listener reconnected; events sent while disconnected were lost")
+		case pq.ListenerEventConnectionAttemptFailed:
+			slog.Warn("event listener could not reconnect", "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(postgresChannel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// nil indica que la conexión se restableció
+			if n == nil {
+				continue
+			}
+			var e Event
+			if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+				slog.Error("invalid event payload", "error", err)
+				continue
+			}
+			if !f.complete(ctx, &e) {
+				continue
+			}
+			f.broker.Deliver(e)
+		case <-time.After(90 * time.Second):
+			// Comprueba que la conexión sigue viva aunque no lleguen eventos
+			go listener.Ping()
+		}
+	}
+}
+
+// complete lee el chirp de un ChirpCreated que llegó sin cuerpo, una sola vez para todos
+// los suscriptores de esta réplica. Devuelve false si no se pudo leer; si se borró
+// entretanto, su ChirpDeleted llega aparte.
+func (f *PostgresFanout) complete(ctx context.Context, e *Event) bool {
+	if e.Type != ChirpCreated || e.Chirp != nil {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+	chirp, err := f.loadChirp(ctx, e.ChirpID)
+	if err != nil {
+		if !database.IsNotFound(err) {
+			slog.Error("could not load chirp for event", "chirp_id", e.ChirpID, "error", err)
+		}
+		return false
+	}
+	e.Chirp = &chirp
+	return true
+}
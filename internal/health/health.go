@@ -0,0 +1,160 @@
+// Package health implementa los endpoints de liveness y readiness.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+)
+
+// CheckFunc comprueba una dependencia; devuelve error si no está lista.
+type CheckFunc func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Checker ejecuta las comprobaciones de readiness y sabe si el servidor se está apagando.
+type Checker struct {
+	timeout  time.Duration
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+// CheckResult es el estado de una comprobación individual.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report es la respuesta de /readyz.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+const (
+	statusOK   = "ok"
+	statusFail = "fail"
+)
+
+// New crea un Checker en el que cada comprobación tiene como máximo timeout para responder.
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// AddCheck registra una comprobación de readiness. Debe llamarse antes de servir peticiones.
+func (c *Checker) AddCheck(name string, check CheckFunc) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetDraining marca el servidor como en apagado: /readyz empieza a fallar para que
+// el balanceador deje de enviarle tráfico.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Liveness responde 200 mientras el proceso esté vivo. No toca dependencias.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	api.RespondWithJSON(w, http.StatusOK, map[string]string{"status": statusOK})
+}
+
+// Readiness ejecuta todas las comprobaciones en paralelo y responde 503 si alguna falla.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+
+	status := http.StatusOK
+	if report.Status != statusOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	api.RespondWithJSON(w, status, report)
+}
+
+// Check ejecuta las comprobaciones y devuelve el resultado de cada una.
+func (c *Checker) Check(ctx context.Context) Report {
+	report := Report{Status: statusOK, Checks: map[string]CheckResult{}}
+	if c.draining.Load() {
+		report.Status = statusFail
+		report.Checks["shutdown"] = CheckResult{Status: statusFail, Error: "server is shutting down"}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := c.run(ctx, nc.check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if result.Status != statusOK {
+				report.Status = statusFail
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:    statusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = statusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// DatabaseCheck hace ping a la base de datos.
+// El error del driver solo se registra en el log; /readyz es público.
+func DatabaseCheck(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			slog.WarnContext(ctx, "readiness: database ping failed", slog.Any("err", err))
+			return errors.New("database is unreachable")
+		}
+		return nil
+	}
+}
+
+// MigrationsCheck comprueba que la última migración aplicada por goose sea la esperada.
+func MigrationsCheck(db *sql.DB, expectedVersion int64) CheckFunc {
+	return func(ctx context.Context) error {
+		var version sql.NullInt64
+		err := db.QueryRowContext(ctx,
+			"SELECT MAX(version_id) FROM goose_db_version WHERE is_applied").Scan(&version)
+		if err != nil {
+			slog.WarnContext(ctx, "readiness: couldn't read schema version", slog.Any("err", err))
+			return errors.New("couldn't read schema version")
+		}
+		if !version.Valid {
+			return errors.New("no migrations applied")
+		}
+		if version.Int64 != expectedVersion {
+			return fmt.Errorf("schema version is %d, expected %d", version.Int64, expectedVersion)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("down") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]CheckFunc
+		draining   bool
+		wantStatus string
+		wantFailed []string
+	}{
+		{
+			name:       "All checks pass",
+			checks:     map[string]CheckFunc{"database": ok, "migrations": ok},
+			wantStatus: statusOK,
+		},
+		{
+			name:       "One check fails",
+			checks:     map[string]CheckFunc{"database": ok, "migrations": failing},
+			wantStatus: statusFail,
+			wantFailed: []string{"migrations"},
+		},
+		{
+			name:       "Check times out",
+			checks:     map[string]CheckFunc{"database": slow},
+			wantStatus: statusFail,
+			wantFailed: []string{"database"},
+		},
+		{
+			name:       "Draining",
+			checks:     map[string]CheckFunc{"database": ok},
+			draining:   true,
+			wantStatus: statusFail,
+			wantFailed: []string{"shutdown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := New(10 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.AddCheck(name, check)
+			}
+			if tt.draining {
+				checker.SetDraining()
+			}
+
+			report := checker.Check(context.Background())
+			if report.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			for _, name := range tt.wantFailed {
+				if report.Checks[name].Status != statusFail {
+					t.Errorf("check %q = %+v, want failure", name, report.Checks[name])
+				}
+			}
+		})
+	}
+}
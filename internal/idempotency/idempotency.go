@@ -0,0 +1,193 @@
+// Package idempotency hace que las peticiones POST con header Idempotency-Key se puedan
+// reintentar sin repetir su efecto: la primera respuesta se guarda y los reintentos con
+// la misma clave la reciben tal cual, sin volver a ejecutar el handler.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+)
+
+// Header es el header con el que el cliente identifica la operación.
+const Header = "Idempotency-Key"
+
+// ReplayedHeader marca las respuestas servidas desde una clave ya usada.
+const ReplayedHeader = "Idempotent-Replayed"
+
+const (
+	// maxKeyLength es la longitud máxima de una clave; un UUID ocupa 36.
+	maxKeyLength = 255
+	// maxBodyBytes es el tamaño máximo del cuerpo que se lee para calcular la huella.
+	maxBodyBytes = 1 << 20
+	// lockTTL es cuánto se reserva una clave mientras la petición original está en curso.
+	// Si el proceso muere antes de guardar la respuesta, la clave se libera al expirar.
+	lockTTL = time.Minute
+)
+
+// Store son las consultas de la tabla idempotency_keys.
+type Store interface {
+	CreateIdempotencyKey(ctx context.Context, arg database.CreateIdempotencyKeyParams) (int64, error)
+	GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg database.CompleteIdempotencyKeyParams) error
+	DeleteIdempotencyKey(ctx context.Context, arg database.DeleteIdempotencyKeyParams) error
+}
+
+// Scope devuelve a quién pertenecen las claves de la petición (por ejemplo, el usuario
+// autenticado), para que dos clientes no compartan claves.
+type Scope func(r *http.Request) string
+
+// Keys aplica Idempotency-Key a handlers HTTP.
+type Keys struct {
+	store Store
+	scope Scope
+	ttl   time.Duration
+}
+
+// New crea un Keys que guarda las respuestas en store durante ttl.
+func New(store Store, scope Scope, ttl time.Duration) *Keys {
+	return &Keys{store: store, scope: scope, ttl: ttl}
+}
+
+// Handler envuelve next. Sin header Idempotency-Key la petición pasa sin más. Con él:
+//   - la primera vez se ejecuta next y se guarda la respuesta, salvo si es un 5xx,
+//     que libera la clave para poder reintentar;
+//   - un reintento con el mismo cuerpo recibe la respuesta guardada;
+//   - un reintento con otro cuerpo recibe 422, y uno que llega mientras la petición
+//     original sigue en curso, 409.
+func (k *Keys) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidIdempotencyKey, nil)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			api.RespondWithError(w, r, http.StatusBadRequest, api.CodeInvalidPayload, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		scope := r.Method + " " + r.URL.Path + " " + k.scope(r)
+		fingerprint := fingerprint(r, body)
+
+		created, err := k.store.CreateIdempotencyKey(ctx, database.CreateIdempotencyKeyParams{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: fingerprint,
+			ExpiresAt:   time.Now().UTC().Add(lockTTL),
+		})
+		if err != nil {
+			api.RespondWithDBError(w, r, api.CodeNotFound, err)
+			return
+		}
+		if created == 0 {
+			k.replay(w, r, scope, key, fingerprint)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		k.save(context.WithoutCancel(ctx), scope, key, rec)
+	})
+}
+
+// replay responde a un reintento con la respuesta guardada de la clave.
+func (k *Keys) replay(w http.ResponseWriter, r *http.Request, scope, key, fingerprint string) {
+	stored, err := k.store.GetIdempotencyKey(r.Context(), database.GetIdempotencyKeyParams{Scope: scope, Key: key})
+	if database.IsNotFound(err) {
+		// Expiró o se liberó entre las dos consultas: el cliente puede reintentar
+		api.RespondWithError(w, r, http.StatusConflict, api.CodeIdempotencyKeyInProgress, nil)
+		return
+	}
+	if err != nil {
+		api.RespondWithDBError(w, r, api.CodeNotFound, err)
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		api.RespondWithError(w, r, http.StatusUnprocessableEntity, api.CodeIdempotencyKeyReused, nil)
+	case stored.StatusCode == 0:
+		api.RespondWithError(w, r, http.StatusConflict, api.CodeIdempotencyKeyInProgress, nil)
+	default:
+		if stored.ContentType != "" {
+			w.Header().Set("Content-Type", stored.ContentType)
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(int(stored.StatusCode))
+		w.Write(stored.ResponseBody)
+	}
+}
+
+// save guarda la respuesta de la petición original o, si fue un error del servidor,
+// libera la clave.
+func (k *Keys) save(ctx context.Context, scope, key string, rec *recorder) {
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	var err error
+	if status >= http.StatusInternalServerError {
+		err = k.store.DeleteIdempotencyKey(ctx, database.DeleteIdempotencyKeyParams{Scope: scope, Key: key})
+	} else {
+		err = k.store.CompleteIdempotencyKey(ctx, database.CompleteIdempotencyKeyParams{
+			Scope:        scope,
+			Key:          key,
+			StatusCode:   int32(status),
+			ContentType:  rec.Header().Get("Content-Type"),
+			ResponseBody: rec.body.Bytes(),
+			ExpiresAt:    time.Now().UTC().Add(k.ttl),
+		})
+	}
+	if err != nil {
+		// La clave queda reservada hasta que expire lockTTL
+		slog.ErrorContext(ctx, "could not save idempotent response", "key", key, "error", err)
+	}
+}
+
+// fingerprint resume lo que identifica a la petición: método, ruta y cuerpo.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\x00")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder escribe la respuesta al cliente y guarda una copia para poder repetirla.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
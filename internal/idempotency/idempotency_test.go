@@ -0,0 +1,109 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/idempotency"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/memstore"
+)
+
+func post(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", "/api/chirps", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(idempotency.Header, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestServerErrorsReleaseTheKey(t *testing.T) {
+	calls := 0
+	h := idempotency.New(memstore.New(), func(*http.Request) string { return "anonymous" }, time.Hour).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	if w := post(h, "k", "{}"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("first attempt = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if w := post(h, "k", "{}"); w.Code != http.StatusCreated || w.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Fatalf("retry after a 5xx = %d, want the handler to run again", w.Code)
+	}
+	if w := post(h, "k", "{}"); w.Code != http.StatusCreated || w.Header().Get(idempotency.ReplayedHeader) != "true" {
+		t.Fatalf("second retry = %d, want the stored response", w.Code)
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
+
+func TestConcurrentRetryConflicts(t *testing.T) {
+	var h http.Handler
+	var inner *httptest.ResponseRecorder
+	h = idempotency.New(memstore.New(), func(*http.Request) string { return "anonymous" }, time.Hour).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Un reintento que llega mientras la petición original sigue en curso
+			inner = post(h, "k", "{}")
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	if w := post(h, "k", "{}"); w.Code != http.StatusCreated {
+		t.Fatalf("original request = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if inner.Code != http.StatusConflict {
+		t.Errorf("concurrent retry = %d, want %d", inner.Code, http.StatusConflict)
+	}
+}
+
+// expiryStore anota los expires_at con los que se reservan y completan las claves.
+type expiryStore struct {
+	*memstore.Store
+	expiresAt []time.Time
+}
+
+func (s *expiryStore) CreateIdempotencyKey(ctx context.Context, arg database.CreateIdempotencyKeyParams) (int64, error) {
+	s.expiresAt = append(s.expiresAt, arg.ExpiresAt)
+	return s.Store.CreateIdempotencyKey(ctx, arg)
+}
+
+func (s *expiryStore) CompleteIdempotencyKey(ctx context.Context, arg database.CompleteIdempotencyKeyParams) error {
+	s.expiresAt = append(s.expiresAt, arg.ExpiresAt)
+	return s.Store.CompleteIdempotencyKey(ctx, arg)
+}
+
+func TestExpiresAtIsUTC(t *testing.T) {
+	// expires_at es TIMESTAMP sin zona: una hora local adelantaría o retrasaría la expiración
+	local := time.Local
+	time.Local = time.FixedZone("CST", -6*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	store := &expiryStore{Store: memstore.New()}
+	h := idempotency.New(store, func(*http.Request) string { return "anonymous" }, time.Hour).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	if w := post(h, "k", "{}"); w.Code != http.StatusCreated {
+		t.Fatalf("request = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if len(store.expiresAt) != 2 {
+		t.Fatalf("stored %d expirations, want 2", len(store.expiresAt))
+	}
+	for i, expiresAt := range store.expiresAt {
+		if loc := expiresAt.Location(); loc != time.UTC {
+			t.Errorf("expiration %d in %v, want UTC", i, loc)
+		}
+	}
+}
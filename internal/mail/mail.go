@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+)
+
+// Sender envía emails transaccionales (verificación de email, avisos de cuenta).
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New devuelve el Sender de cfg: SMTP si hay servidor configurado y, si no, un
+// LogSender sobre stderr.
+func New(cfg config.MailConfig) Sender {
+	if cfg.SMTPAddr == "" {
+		return NewLogSender(os.Stderr)
+	}
+	return NewSMTPSender(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+}
+
+// LogSender escribe los emails en w en lugar de enviarlos. Pensado para desarrollo.
+// Usa su propio logger y no el de slog: el log de la aplicación redacta emails y
+// tokens, y un email de verificación sin su token no sirve de nada.
+type LogSender struct {
+	logger *log.Logger
+}
+
+// NewLogSender crea un LogSender que escribe en w.
+func NewLogSender(w io.Writer) *LogSender {
+	return &LogSender{logger: log.New(w, "", log.LstdFlags)}
+}
+
+// Send -
+func (s *LogSender) Send(ctx context.Context, to, subject, body string) error {
+	s.logger.Printf("mail to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
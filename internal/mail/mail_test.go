@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/middleware"
+)
+
+const token = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestLogSenderKeepsTokens(t *testing.T) {
+	// El logger por defecto de la aplicación redacta los tokens; LogSender no debe usarlo
+	var appLog bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(middleware.NewLogger(&appLog, slog.LevelInfo))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	var out bytes.Buffer
+	if err := NewLogSender(&out).Send(context.Background(), "walt@breakingbad.com", "Confirm", "token:\n"+token); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if !strings.Contains(out.String(), token) || !strings.Contains(out.String(), "walt@breakingbad.com") {
+		t.Errorf("output = %q, want the recipient and the token", out.String())
+	}
+	if appLog.Len() != 0 {
+		t.Errorf("application log = %q, want nothing", appLog.String())
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go serveSMTP(t, ln, received)
+
+	sender := NewSMTPSender(ln.Addr().String(), "", "", "Chirpy <no-reply@chirpy.example>")
+	if err := sender.Send(context.Background(), "walt@breakingbad.com", "Confirm your new email", "token:\n"+token); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	msg := <-received
+	for _, want := range []string{
+		"MAIL FROM:<no-reply@chirpy.example>",
+		"RCPT TO:<walt@breakingbad.com>",
+		"To: <walt@breakingbad.com>\r\n",
+		"Subject: Confirm your new email\r\n",
+		"token:\r\n" + token,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+
+	if err := sender.Send(context.Background(), "walt@breakingbad.com\r\nBcc: jesse@breakingbad.com", "Hi", "hi"); err == nil {
+		t.Error("Send() with a header in the recipient succeeded, want an error")
+	}
+}
+
+// serveSMTP atiende una sola conexión SMTP sin extensiones y envía a received todo
+// lo que escribió el cliente.
+func serveSMTP(t *testing.T, ln net.Listener, received chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Errorf("accept: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var transcript strings.Builder
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+	reply("220 localhost ESMTP")
+	inData := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			received <- transcript.String()
+			return
+		}
+		transcript.WriteString(line)
+		switch {
+		case inData && line == ".\r\n":
+			inData = false
+			reply("250 OK")
+		case inData:
+		case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(line, "DATA"):
+			inData = true
+			reply("354 Go ahead")
+		case strings.HasPrefix(line, "QUIT"):
+			reply("221 Bye")
+			received <- transcript.String()
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateChirp(ctx context.Context, arg database.CreateChirpParams) (database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.Chirp{}, foreignKeyViolation("chirps_user_id_fkey")
+	}
+	now := s.now()
+	chirp := database.Chirp{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Body:      arg.Body,
+		UserID:    arg.UserID,
+		ReplyToID: arg.ReplyToID,
+	}
+	s.chirps[chirp.ID] = chirp
+	return chirp, nil
+}
+
+func (s *Store) GetChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chirp, ok := s.chirps[id]
+	if !ok {
+		return database.Chirp{}, sql.ErrNoRows
+	}
+	return chirp, nil
+}
+
+// GetChirpWithAuthor no devuelve chirps de cuentas pendientes de eliminación.
+func (s *Store) GetChirpWithAuthor(ctx context.Context, id uuid.UUID) (database.GetChirpWithAuthorRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chirp, ok := s.chirps[id]
+	if !ok {
+		return database.GetChirpWithAuthorRow{}, sql.ErrNoRows
+	}
+	author := s.users[chirp.UserID]
+	if author.DeletedAt.Valid {
+		return database.GetChirpWithAuthorRow{}, sql.ErrNoRows
+	}
+	return database.GetChirpWithAuthorRow(withAuthor(chirp, author)), nil
+}
+
+func (s *Store) GetChirps(ctx context.Context) ([]database.GetChirpsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.visibleChirps(func(database.Chirp) bool { return true }), nil
+}
+
+// GetChirpsForViewer excluye a los autores que el lector silenció y los bloqueos en ambos sentidos.
+func (s *Store) GetChirpsForViewer(ctx context.Context, viewerID uuid.UUID) ([]database.GetChirpsForViewerRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := s.visibleChirps(func(chirp database.Chirp) bool {
+		_, muted := s.mutes[pair{viewerID, chirp.UserID}]
+		_, blocked := s.blocks[pair{viewerID, chirp.UserID}]
+		_, blockedBy := s.blocks[pair{chirp.UserID, viewerID}]
+		return !muted && !blocked && !blockedBy
+	})
+
+	result := make([]database.GetChirpsForViewerRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, database.GetChirpsForViewerRow(row))
+	}
+	return result, nil
+}
+
+// visibleChirps devuelve, ordenados por created_at, los chirps de autores no eliminados que cumplen keep.
+// Debe llamarse con el mutex tomado.
+func (s *Store) visibleChirps(keep func(database.Chirp) bool) []database.GetChirpsRow {
+	var rows []database.GetChirpsRow
+	for _, chirp := range s.chirps {
+		author := s.users[chirp.UserID]
+		if author.DeletedAt.Valid || !keep(chirp) {
+			continue
+		}
+		rows = append(rows, withAuthor(chirp, author))
+	}
+	sortByCreatedAt(rows, func(r database.GetChirpsRow) time.Time { return r.Chirp.CreatedAt }, false)
+	return rows
+}
+
+func withAuthor(chirp database.Chirp, author database.User) database.GetChirpsRow {
+	return database.GetChirpsRow{
+		Chirp:       chirp,
+		Username:    author.Username,
+		DisplayName: author.DisplayName,
+		AvatarUrl:   author.AvatarUrl,
+	}
+}
+
+func (s *Store) ListChirpsByUser(ctx context.Context, userID uuid.UUID) ([]database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var chirps []database.Chirp
+	for _, chirp := range s.chirps {
+		if chirp.UserID == userID {
+			chirps = append(chirps, chirp)
+		}
+	}
+	sortByCreatedAt(chirps, func(c database.Chirp) time.Time { return c.CreatedAt }, false)
+	return chirps, nil
+}
+
+func (s *Store) HideChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chirp, ok := s.chirps[id]
+	if !ok {
+		return database.Chirp{}, sql.ErrNoRows
+	}
+	chirp.HiddenAt = s.nullNow()
+	chirp.UpdatedAt = chirp.HiddenAt.Time
+	s.chirps[id] = chirp
+	return chirp, nil
+}
+
+func (s *Store) DeleteChirp(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteChirp(id)
+	return nil
+}
+
+// deleteChirp borra el chirp con sus likes y deja sin chirp a sus reportes y
+// notificaciones (ON DELETE SET NULL). Las respuestas conservan reply_to_id, que no
+// tiene clave foránea. Debe llamarse con el mutex tomado.
+func (s *Store) deleteChirp(id uuid.UUID) {
+	delete(s.chirps, id)
+	for key := range s.likes {
+		if key.to == id {
+			delete(s.likes, key)
+		}
+	}
+	for reportID, report := range s.reports {
+		if report.ChirpID.Valid && report.ChirpID.UUID == id {
+			report.ChirpID = uuid.NullUUID{}
+			s.reports[reportID] = report
+		}
+	}
+	s.unlinkChirpNotifications(id)
+}
@@ -0,0 +1,85 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+)
+
+// idempotencyKey es la clave primaria compuesta de idempotency_keys
+type idempotencyKey struct {
+	scope, key string
+}
+
+// CreateIdempotencyKey reserva la clave y devuelve 1, o 0 si ya existe y no expiró.
+func (s *Store) CreateIdempotencyKey(ctx context.Context, arg database.CreateIdempotencyKeyParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	pk := idempotencyKey{arg.Scope, arg.Key}
+	if existing, ok := s.idempotencyKeys[pk]; ok && existing.ExpiresAt.After(now) {
+		return 0, nil
+	}
+	s.idempotencyKeys[pk] = database.IdempotencyKey{
+		Scope:        arg.Scope,
+		Key:          arg.Key,
+		Fingerprint:  arg.Fingerprint,
+		ResponseBody: []byte{},
+		CreatedAt:    now,
+		ExpiresAt:    arg.ExpiresAt,
+	}
+	return 1, nil
+}
+
+func (s *Store) GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.idempotencyKeys[idempotencyKey{arg.Scope, arg.Key}]
+	if !ok || !k.ExpiresAt.After(s.now()) {
+		return database.IdempotencyKey{}, sql.ErrNoRows
+	}
+	return k, nil
+}
+
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, arg database.CompleteIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pk := idempotencyKey{arg.Scope, arg.Key}
+	k, ok := s.idempotencyKeys[pk]
+	if !ok {
+		return nil
+	}
+	k.StatusCode = arg.StatusCode
+	k.ContentType = arg.ContentType
+	k.ResponseBody = append([]byte(nil), arg.ResponseBody...)
+	k.ExpiresAt = arg.ExpiresAt
+	s.idempotencyKeys[pk] = k
+	return nil
+}
+
+func (s *Store) DeleteIdempotencyKey(ctx context.Context, arg database.DeleteIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.idempotencyKeys, idempotencyKey{arg.Scope, arg.Key})
+	return nil
+}
+
+func (s *Store) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var purged int64
+	for pk, k := range s.idempotencyKeys {
+		if !k.ExpiresAt.After(now) {
+			delete(s.idempotencyKeys, pk)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,219 @@
+// Package memstore implementa service.Store en memoria, con la misma semántica que las
+// consultas de Postgres: restricciones de unicidad y CHECK, borrados en cascada y
+// expiración/revocación de refresh tokens. Está pensado para tests y desarrollo local.
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Store guarda todas las tablas en mapas protegidos por un único mutex.
+// El valor cero no es usable: usa New.
+type Store struct {
+	mu sync.Mutex
+
+	// txMu serializa las transacciones de InTx
+	txMu sync.Mutex
+
+	// last garantiza que NOW() sea estrictamente creciente, para que el orden por created_at
+	// sea determinista aunque dos filas se creen en el mismo instante
+	last time.Time
+
+	users              map[uuid.UUID]database.User
+	chirps             map[uuid.UUID]database.Chirp
+	refreshTokens      map[string]database.RefreshToken
+	emailVerifications map[string]database.EmailVerification
+	blocks             map[pair]database.Block
+	mutes              map[pair]database.Mute
+	likes              map[pair]database.Like
+	follows            map[pair]database.Follow
+	reports            map[uuid.UUID]database.Report
+	auditLogs          map[uuid.UUID]database.AdminAuditLog
+	subscriptionEvents map[uuid.UUID]database.SubscriptionEvent
+	idempotencyKeys    map[idempotencyKey]database.IdempotencyKey
+
+	notifications           map[uuid.UUID]database.Notification
+	notificationActors      map[pair]database.NotificationActor
+	notificationPreferences map[preferenceKey]database.NotificationPreference
+}
+
+// pair es la clave primaria compuesta de blocks, mutes, likes, follows y notification_actors
+type pair struct {
+	from, to uuid.UUID
+}
+
+// New devuelve un Store vacío.
+func New() *Store {
+	return &Store{
+		users:              make(map[uuid.UUID]database.User),
+		chirps:             make(map[uuid.UUID]database.Chirp),
+		refreshTokens:      make(map[string]database.RefreshToken),
+		emailVerifications: make(map[string]database.EmailVerification),
+		blocks:             make(map[pair]database.Block),
+		mutes:              make(map[pair]database.Mute),
+		likes:              make(map[pair]database.Like),
+		follows:            make(map[pair]database.Follow),
+		reports:            make(map[uuid.UUID]database.Report),
+		auditLogs:          make(map[uuid.UUID]database.AdminAuditLog),
+		subscriptionEvents: make(map[uuid.UUID]database.SubscriptionEvent),
+		idempotencyKeys:    make(map[idempotencyKey]database.IdempotencyKey),
+
+		notifications:           make(map[uuid.UUID]database.Notification),
+		notificationActors:      make(map[pair]database.NotificationActor),
+		notificationPreferences: make(map[preferenceKey]database.NotificationPreference),
+	}
+}
+
+// now imita NOW() de Postgres: UTC con precisión de microsegundos.
+// Debe llamarse con el mutex tomado.
+func (s *Store) now() time.Time {
+	t := time.Now().UTC().Truncate(time.Microsecond)
+	if !t.After(s.last) {
+		t = s.last.Add(time.Microsecond)
+	}
+	s.last = t
+	return t
+}
+
+// nullNow devuelve now() como sql.NullTime válido.
+func (s *Store) nullNow() sql.NullTime {
+	return sql.NullTime{Time: s.now(), Valid: true}
+}
+
+// Errores con los mismos códigos SQLSTATE que devolvería Postgres, para que
+// database.ClassifyError los clasifique igual.
+
+func uniqueViolation(constraint string) error {
+	return &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint", Constraint: constraint}
+}
+
+func foreignKeyViolation(constraint string) error {
+	return &pq.Error{Code: "23503", Message: "insert or update violates foreign key constraint", Constraint: constraint}
+}
+
+func checkViolation(constraint string) error {
+	return &pq.Error{Code: "23514", Message: "new row violates check constraint", Constraint: constraint}
+}
+
+// Reset borra todos los usuarios y, en cascada, sus datos (DELETE FROM users).
+func (s *Store) Reset(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.users {
+		s.deleteUser(id)
+	}
+	return nil
+}
+
+// PurgeDeletedUsers elimina definitivamente las cuentas cuyo periodo de gracia terminó.
+func (s *Store) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var purged int64
+	for id, user := range s.users {
+		if user.DeletedAt.Valid && user.PurgeAfter.Valid && !user.PurgeAfter.Time.After(now) {
+			s.deleteUser(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// deleteUser borra un usuario aplicando las reglas ON DELETE del esquema.
+// Debe llamarse con el mutex tomado.
+func (s *Store) deleteUser(id uuid.UUID) {
+	delete(s.users, id)
+
+	// ON DELETE CASCADE
+	for chirpID, chirp := range s.chirps {
+		if chirp.UserID == id {
+			s.deleteChirp(chirpID)
+		}
+	}
+	for token, rt := range s.refreshTokens {
+		if rt.UserID == id {
+			delete(s.refreshTokens, token)
+		}
+	}
+	for token, ev := range s.emailVerifications {
+		if ev.UserID == id {
+			delete(s.emailVerifications, token)
+		}
+	}
+	for key := range s.blocks {
+		if key.from == id || key.to == id {
+			delete(s.blocks, key)
+		}
+	}
+	for key := range s.mutes {
+		if key.from == id || key.to == id {
+			delete(s.mutes, key)
+		}
+	}
+	for key := range s.likes {
+		if key.from == id {
+			delete(s.likes, key)
+		}
+	}
+	for key := range s.follows {
+		if key.from == id || key.to == id {
+			delete(s.follows, key)
+		}
+	}
+	for eventID, event := range s.subscriptionEvents {
+		if event.UserID == id {
+			delete(s.subscriptionEvents, eventID)
+		}
+	}
+	for reportID, report := range s.reports {
+		if report.ReporterID == id || report.ReportedUserID == id {
+			delete(s.reports, reportID)
+			continue
+		}
+		// ON DELETE SET NULL
+		if report.ResolvedBy.Valid && report.ResolvedBy.UUID == id {
+			report.ResolvedBy = uuid.NullUUID{}
+			s.reports[reportID] = report
+		}
+	}
+	for logID, entry := range s.auditLogs {
+		if entry.ActorID.Valid && entry.ActorID.UUID == id {
+			entry.ActorID = uuid.NullUUID{}
+			s.auditLogs[logID] = entry
+		}
+	}
+	s.deleteUserNotifications(id)
+}
+
+// paginate aplica LIMIT y OFFSET a una lista ya ordenada.
+func paginate[T any](rows []T, limit, offset int32) []T {
+	if offset < 0 || int(offset) >= len(rows) {
+		return []T{}
+	}
+	rows = rows[offset:]
+	if limit >= 0 && int(limit) < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// sortByCreatedAt ordena rows por created_at, ascendente o descendente.
+func sortByCreatedAt[T any](rows []T, createdAt func(T) time.Time, desc bool) {
+	sort.Slice(rows, func(i, j int) bool {
+		if desc {
+			return createdAt(rows[i]).After(createdAt(rows[j]))
+		}
+		return createdAt(rows[i]).Before(createdAt(rows[j]))
+	})
+}
@@ -0,0 +1,167 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+)
+
+func TestUniqueConstraints(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	_, err := s.CreateUser(ctx, database.CreateUserParams{
+		Email:    "walt@breakingbad.com",
+		Username: sql.NullString{String: "Heisenberg", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		arg  database.CreateUserParams
+	}{
+		{
+			name: "Duplicate email",
+			arg:  database.CreateUserParams{Email: "walt@breakingbad.com"},
+		},
+		{
+			name: "Username differs only in case",
+			arg: database.CreateUserParams{
+				Email:    "other@breakingbad.com",
+				Username: sql.NullString{String: "heisenberg", Valid: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateUser(ctx, tt.arg)
+			if !database.IsConflict(err) {
+				t.Errorf("CreateUser() error = %v, want conflict", err)
+			}
+		})
+	}
+}
+
+func TestPurgeCascades(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	walt, _ := s.CreateUser(ctx, database.CreateUserParams{Email: "walt@breakingbad.com"})
+	hank, _ := s.CreateUser(ctx, database.CreateUserParams{Email: "hank@dea.gov"})
+	chirp, err := s.CreateChirp(ctx, database.CreateChirpParams{Body: "Say my name", UserID: walt.ID})
+	if err != nil {
+		t.Fatalf("CreateChirp() error = %v", err)
+	}
+	s.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{Token: "walt-token", UserID: walt.ID, ExpiresAt: time.Now().Add(time.Hour)})
+	s.BlockUser(ctx, database.BlockUserParams{BlockerID: hank.ID, BlockedID: walt.ID})
+	report, _ := s.CreateReport(ctx, database.CreateReportParams{
+		ReporterID:     hank.ID,
+		ReportedUserID: walt.ID,
+		ChirpID:        uuid.NullUUID{UUID: chirp.ID, Valid: true},
+		Reason:         "violence",
+	})
+	audit, _ := s.CreateAuditLog(ctx, database.CreateAuditLogParams{ActorID: uuid.NullUUID{UUID: walt.ID, Valid: true}, Action: "ban"})
+
+	s.SoftDeleteUser(ctx, database.SoftDeleteUserParams{ID: walt.ID, PurgeAfter: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}})
+	if purged, _ := s.PurgeDeletedUsers(ctx); purged != 1 {
+		t.Fatalf("PurgeDeletedUsers() = %d, want 1", purged)
+	}
+
+	if _, err := s.GetChirp(ctx, chirp.ID); !database.IsNotFound(err) {
+		t.Errorf("GetChirp() error = %v, want not found", err)
+	}
+	if _, err := s.GetUserFromRefreshToken(ctx, "walt-token"); !database.IsNotFound(err) {
+		t.Errorf("GetUserFromRefreshToken() error = %v, want not found", err)
+	}
+	if blocks, _ := s.ListBlocks(ctx, hank.ID); len(blocks) != 0 {
+		t.Errorf("ListBlocks() = %v, want none", blocks)
+	}
+	if _, err := s.GetReport(ctx, report.ID); !database.IsNotFound(err) {
+		t.Errorf("GetReport() error = %v, want not found", err)
+	}
+	logs, _ := s.ListAuditLogs(ctx, database.ListAuditLogsParams{Limit: 10})
+	if len(logs) != 1 || logs[0].ID != audit.ID || logs[0].ActorID.Valid {
+		t.Errorf("ListAuditLogs() = %+v, want actor set to NULL", logs)
+	}
+}
+
+func TestCheckConstraints(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	walt, _ := s.CreateUser(ctx, database.CreateUserParams{Email: "walt@breakingbad.com"})
+
+	if _, err := s.SetUserRole(ctx, database.SetUserRoleParams{ID: walt.ID, Role: "kingpin"}); err == nil {
+		t.Error("SetUserRole() with an unknown role should fail")
+	}
+	if err := s.BlockUser(ctx, database.BlockUserParams{BlockerID: walt.ID, BlockedID: walt.ID}); err == nil {
+		t.Error("BlockUser() on self should fail")
+	}
+	if _, err := s.CreateChirp(ctx, database.CreateChirpParams{Body: "ghost", UserID: uuid.New()}); err == nil {
+		t.Error("CreateChirp() for a missing user should fail")
+	}
+}
+
+func TestInTxRollsBack(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	walt, _ := s.CreateUser(ctx, database.CreateUserParams{Email: "walt@breakingbad.com"})
+	failure := errors.New("boom")
+
+	err := s.InTx(ctx, func(q service.Queries) error {
+		if _, err := q.CreateChirp(ctx, database.CreateChirpParams{Body: "Say my name", UserID: walt.ID}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("InTx() error = %v, want %v", err, failure)
+	}
+
+	chirps, _ := s.ListChirpsByUser(ctx, walt.ID)
+	if len(chirps) != 0 {
+		t.Errorf("chirps after rollback = %d, want 0", len(chirps))
+	}
+}
+
+func TestIdempotencyKeysExpire(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	reserve := func(expiresAt time.Time) int64 {
+		t.Helper()
+		created, err := s.CreateIdempotencyKey(ctx, database.CreateIdempotencyKeyParams{
+			Scope:       "POST /api/chirps anonymous",
+			Key:         "k",
+			Fingerprint: "f",
+			ExpiresAt:   expiresAt,
+		})
+		if err != nil {
+			t.Fatalf("CreateIdempotencyKey: %v", err)
+		}
+		return created
+	}
+
+	if created := reserve(time.Now().Add(-time.Second)); created != 1 {
+		t.Fatalf("first reservation = %d, want 1", created)
+	}
+	// Una clave expirada se puede volver a reservar
+	if created := reserve(time.Now().Add(time.Hour)); created != 1 {
+		t.Fatalf("reservation over an expired key = %d, want 1", created)
+	}
+	if created := reserve(time.Now().Add(time.Hour)); created != 0 {
+		t.Fatalf("reservation over a live key = %d, want 0", created)
+	}
+	if purged, _ := s.PurgeExpiredIdempotencyKeys(ctx); purged != 0 {
+		t.Errorf("purged %d live keys", purged)
+	}
+}
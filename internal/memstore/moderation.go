@@ -0,0 +1,275 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// motivos válidos según el CHECK de la columna reports.reason
+var validReasons = map[string]bool{
+	"spam": true, "harassment": true, "hate_speech": true, "violence": true,
+	"sexual_content": true, "misinformation": true, "impersonation": true, "other": true,
+}
+
+// BlockUser no falla si el bloqueo ya existía (ON CONFLICT DO NOTHING).
+func (s *Store) BlockUser(ctx context.Context, arg database.BlockUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := pair{arg.BlockerID, arg.BlockedID}
+	if err := s.checkPair(key, "blocks"); err != nil {
+		return err
+	}
+	if _, ok := s.blocks[key]; !ok {
+		s.blocks[key] = database.Block{BlockerID: arg.BlockerID, BlockedID: arg.BlockedID, CreatedAt: s.now()}
+	}
+	return nil
+}
+
+func (s *Store) UnblockUser(ctx context.Context, arg database.UnblockUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.blocks, pair{arg.BlockerID, arg.BlockedID})
+	return nil
+}
+
+func (s *Store) IsBlocked(ctx context.Context, arg database.IsBlockedParams) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.blocks[pair{arg.BlockerID, arg.BlockedID}]
+	return ok, nil
+}
+
+func (s *Store) ListBlocks(ctx context.Context, blockerID uuid.UUID) ([]database.Block, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var blocks []database.Block
+	for key, block := range s.blocks {
+		if key.from == blockerID {
+			blocks = append(blocks, block)
+		}
+	}
+	sortByCreatedAt(blocks, func(b database.Block) time.Time { return b.CreatedAt }, true)
+	return blocks, nil
+}
+
+func (s *Store) ListBlockers(ctx context.Context, blockedID uuid.UUID) ([]database.Block, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var blocks []database.Block
+	for key, block := range s.blocks {
+		if key.to == blockedID {
+			blocks = append(blocks, block)
+		}
+	}
+	sortByCreatedAt(blocks, func(b database.Block) time.Time { return b.CreatedAt }, true)
+	return blocks, nil
+}
+
+// MuteUser no falla si el silenciado ya existía (ON CONFLICT DO NOTHING).
+func (s *Store) MuteUser(ctx context.Context, arg database.MuteUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := pair{arg.MuterID, arg.MutedID}
+	if err := s.checkPair(key, "mutes"); err != nil {
+		return err
+	}
+	if _, ok := s.mutes[key]; !ok {
+		s.mutes[key] = database.Mute{MuterID: arg.MuterID, MutedID: arg.MutedID, CreatedAt: s.now()}
+	}
+	return nil
+}
+
+func (s *Store) UnmuteUser(ctx context.Context, arg database.UnmuteUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.mutes, pair{arg.MuterID, arg.MutedID})
+	return nil
+}
+
+func (s *Store) ListMutes(ctx context.Context, muterID uuid.UUID) ([]database.Mute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var mutes []database.Mute
+	for key, mute := range s.mutes {
+		if key.from == muterID {
+			mutes = append(mutes, mute)
+		}
+	}
+	sortByCreatedAt(mutes, func(m database.Mute) time.Time { return m.CreatedAt }, true)
+	return mutes, nil
+}
+
+// checkPair valida las claves foráneas y el CHECK (from <> to) de blocks y mutes.
+// Debe llamarse con el mutex tomado.
+func (s *Store) checkPair(key pair, table string) error {
+	if key.from == key.to {
+		return checkViolation(table + "_check")
+	}
+	if _, ok := s.users[key.from]; !ok {
+		return foreignKeyViolation(table + "_fkey")
+	}
+	if _, ok := s.users[key.to]; !ok {
+		return foreignKeyViolation(table + "_fkey")
+	}
+	return nil
+}
+
+func (s *Store) CreateReport(ctx context.Context, arg database.CreateReportParams) (database.Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !validReasons[arg.Reason] {
+		return database.Report{}, checkViolation("reports_reason_check")
+	}
+	if _, ok := s.users[arg.ReporterID]; !ok {
+		return database.Report{}, foreignKeyViolation("reports_reporter_id_fkey")
+	}
+	if _, ok := s.users[arg.ReportedUserID]; !ok {
+		return database.Report{}, foreignKeyViolation("reports_reported_user_id_fkey")
+	}
+	if arg.ChirpID.Valid {
+		if _, ok := s.chirps[arg.ChirpID.UUID]; !ok {
+			return database.Report{}, foreignKeyViolation("reports_chirp_id_fkey")
+		}
+	}
+	now := s.now()
+	report := database.Report{
+		ID:             uuid.New(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ReporterID:     arg.ReporterID,
+		ReportedUserID: arg.ReportedUserID,
+		ChirpID:        arg.ChirpID,
+		Reason:         arg.Reason,
+		Details:        arg.Details,
+		Status:         "open",
+	}
+	s.reports[report.ID] = report
+	return report, nil
+}
+
+func (s *Store) GetReport(ctx context.Context, id uuid.UUID) (database.Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report, ok := s.reports[id]
+	if !ok {
+		return database.Report{}, sql.ErrNoRows
+	}
+	return report, nil
+}
+
+func (s *Store) ListReports(ctx context.Context, arg database.ListReportsParams) ([]database.Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reports []database.Report
+	for _, report := range s.reports {
+		if report.Status == arg.Status {
+			reports = append(reports, report)
+		}
+	}
+	sortByCreatedAt(reports, func(r database.Report) time.Time { return r.CreatedAt }, false)
+	return paginate(reports, arg.Limit, arg.Offset), nil
+}
+
+// ResolveReport devuelve sql.ErrNoRows si el reporte no existe o ya estaba resuelto.
+func (s *Store) ResolveReport(ctx context.Context, arg database.ResolveReportParams) (database.Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report, ok := s.reports[arg.ID]
+	if !ok || report.Status != "open" {
+		return database.Report{}, sql.ErrNoRows
+	}
+	if arg.ResolvedBy.Valid {
+		if _, ok := s.users[arg.ResolvedBy.UUID]; !ok {
+			return database.Report{}, foreignKeyViolation("reports_resolved_by_fkey")
+		}
+	}
+	report.Status = "resolved"
+	report.Resolution = arg.Resolution
+	report.ResolvedBy = arg.ResolvedBy
+	report.ResolvedAt = s.nullNow()
+	report.UpdatedAt = report.ResolvedAt.Time
+	s.reports[arg.ID] = report
+	return report, nil
+}
+
+func (s *Store) CreateAuditLog(ctx context.Context, arg database.CreateAuditLogParams) (database.AdminAuditLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if arg.ActorID.Valid {
+		if _, ok := s.users[arg.ActorID.UUID]; !ok {
+			return database.AdminAuditLog{}, foreignKeyViolation("admin_audit_log_actor_id_fkey")
+		}
+	}
+	entry := database.AdminAuditLog{
+		ID:         uuid.New(),
+		CreatedAt:  s.now(),
+		ActorID:    arg.ActorID,
+		Action:     arg.Action,
+		TargetType: arg.TargetType,
+		TargetID:   arg.TargetID,
+		Details:    arg.Details,
+	}
+	s.auditLogs[entry.ID] = entry
+	return entry, nil
+}
+
+func (s *Store) ListAuditLogs(ctx context.Context, arg database.ListAuditLogsParams) ([]database.AdminAuditLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]database.AdminAuditLog, 0, len(s.auditLogs))
+	for _, entry := range s.auditLogs {
+		entries = append(entries, entry)
+	}
+	sortByCreatedAt(entries, func(e database.AdminAuditLog) time.Time { return e.CreatedAt }, true)
+	return paginate(entries, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) CreateSubscriptionEvent(ctx context.Context, arg database.CreateSubscriptionEventParams) (database.SubscriptionEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.SubscriptionEvent{}, foreignKeyViolation("subscription_events_user_id_fkey")
+	}
+	event := database.SubscriptionEvent{
+		ID:        uuid.New(),
+		CreatedAt: s.now(),
+		UserID:    arg.UserID,
+		Event:     arg.Event,
+		Source:    arg.Source,
+	}
+	s.subscriptionEvents[event.ID] = event
+	return event, nil
+}
+
+func (s *Store) ListSubscriptionEvents(ctx context.Context, userID uuid.UUID) ([]database.SubscriptionEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []database.SubscriptionEvent
+	for _, event := range s.subscriptionEvents {
+		if event.UserID == userID {
+			events = append(events, event)
+		}
+	}
+	sortByCreatedAt(events, func(e database.SubscriptionEvent) time.Time { return e.CreatedAt }, false)
+	return events, nil
+}
@@ -0,0 +1,269 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// preferenceKey es la clave primaria compuesta de notification_preferences
+type preferenceKey struct {
+	userID uuid.UUID
+	typ    string
+}
+
+func (s *Store) GetUnreadNotificationGroup(ctx context.Context, arg database.GetUnreadNotificationGroupParams) (database.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest database.Notification
+	found := false
+	for _, n := range s.notifications {
+		if n.UserID != arg.UserID || n.Type != arg.Type || n.GroupKey != arg.GroupKey ||
+			n.ReadAt.Valid || !n.UpdatedAt.After(arg.Since) {
+			continue
+		}
+		if !found || n.UpdatedAt.After(latest.UpdatedAt) {
+			latest, found = n, true
+		}
+	}
+	if !found {
+		return database.Notification{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+func (s *Store) CreateNotification(ctx context.Context, arg database.CreateNotificationParams) (database.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.Notification{}, foreignKeyViolation("notifications_user_id_fkey")
+	}
+	if err := s.checkNotificationRefs(arg.ChirpID, arg.ActorID); err != nil {
+		return database.Notification{}, err
+	}
+	now := s.now()
+	n := database.Notification{
+		ID:         uuid.New(),
+		UserID:     arg.UserID,
+		Type:       arg.Type,
+		GroupKey:   arg.GroupKey,
+		ChirpID:    arg.ChirpID,
+		ActorID:    arg.ActorID,
+		ActorCount: 1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	s.notifications[n.ID] = n
+	return n, nil
+}
+
+// checkNotificationRefs valida las claves foráneas opcionales de notifications.
+// Debe llamarse con el mutex tomado.
+func (s *Store) checkNotificationRefs(chirpID, actorID uuid.NullUUID) error {
+	if chirpID.Valid {
+		if _, ok := s.chirps[chirpID.UUID]; !ok {
+			return foreignKeyViolation("notifications_chirp_id_fkey")
+		}
+	}
+	if actorID.Valid {
+		if _, ok := s.users[actorID.UUID]; !ok {
+			return foreignKeyViolation("notifications_actor_id_fkey")
+		}
+	}
+	return nil
+}
+
+// AddNotificationActor devuelve 1 si el actor es nuevo en la notificación y 0 si ya estaba.
+func (s *Store) AddNotificationActor(ctx context.Context, arg database.AddNotificationActorParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.notifications[arg.NotificationID]; !ok {
+		return 0, foreignKeyViolation("notification_actors_notification_id_fkey")
+	}
+	if _, ok := s.users[arg.ActorID]; !ok {
+		return 0, foreignKeyViolation("notification_actors_actor_id_fkey")
+	}
+	key := pair{arg.NotificationID, arg.ActorID}
+	if _, ok := s.notificationActors[key]; ok {
+		return 0, nil
+	}
+	s.notificationActors[key] = database.NotificationActor{
+		NotificationID: arg.NotificationID,
+		ActorID:        arg.ActorID,
+		CreatedAt:      s.now(),
+	}
+	return 1, nil
+}
+
+func (s *Store) BumpNotification(ctx context.Context, arg database.BumpNotificationParams) (database.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notifications[arg.ID]
+	if !ok {
+		return database.Notification{}, sql.ErrNoRows
+	}
+	if err := s.checkNotificationRefs(arg.ChirpID, arg.ActorID); err != nil {
+		return database.Notification{}, err
+	}
+	n.ChirpID = arg.ChirpID
+	n.ActorID = arg.ActorID
+	n.ActorCount += arg.NewActors
+	n.UpdatedAt = s.now()
+	s.notifications[n.ID] = n
+	return n, nil
+}
+
+// ListNotifications ordena por updated_at descendente, como la consulta de Postgres.
+func (s *Store) ListNotifications(ctx context.Context, arg database.ListNotificationsParams) ([]database.ListNotificationsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []database.ListNotificationsRow
+	for _, n := range s.notifications {
+		if n.UserID != arg.UserID || (arg.UnreadOnly && n.ReadAt.Valid) {
+			continue
+		}
+		row := database.ListNotificationsRow{Notification: n}
+		// LEFT JOIN users AS actors
+		if actor, ok := s.users[n.ActorID.UUID]; n.ActorID.Valid && ok {
+			row.Username = actor.Username
+			row.DisplayName = sql.NullString{String: actor.DisplayName, Valid: true}
+			row.AvatarUrl = sql.NullString{String: actor.AvatarUrl, Valid: true}
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Notification.UpdatedAt.After(rows[j].Notification.UpdatedAt)
+	})
+	return paginate(rows, arg.RowLimit, arg.RowOffset), nil
+}
+
+func (s *Store) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, n := range s.notifications {
+		if n.UserID == userID && !n.ReadAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MarkNotificationRead devuelve sql.ErrNoRows si la notificación no es del usuario.
+func (s *Store) MarkNotificationRead(ctx context.Context, arg database.MarkNotificationReadParams) (database.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notifications[arg.ID]
+	if !ok || n.UserID != arg.UserID {
+		return database.Notification{}, sql.ErrNoRows
+	}
+	if !n.ReadAt.Valid {
+		n.ReadAt = s.nullNow()
+		s.notifications[n.ID] = n
+	}
+	return n, nil
+}
+
+func (s *Store) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.nullNow()
+	var read int64
+	for id, n := range s.notifications {
+		if n.UserID == userID && !n.ReadAt.Valid {
+			n.ReadAt = now
+			s.notifications[id] = n
+			read++
+		}
+	}
+	return read, nil
+}
+
+func (s *Store) ListNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var prefs []database.NotificationPreference
+	for key, pref := range s.notificationPreferences {
+		if key.userID == userID {
+			prefs = append(prefs, pref)
+		}
+	}
+	sort.Slice(prefs, func(i, j int) bool { return prefs[i].Type < prefs[j].Type })
+	return prefs, nil
+}
+
+func (s *Store) SetNotificationPreference(ctx context.Context, arg database.SetNotificationPreferenceParams) (database.NotificationPreference, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.NotificationPreference{}, foreignKeyViolation("notification_preferences_user_id_fkey")
+	}
+	pref := database.NotificationPreference{
+		UserID:    arg.UserID,
+		Type:      arg.Type,
+		Enabled:   arg.Enabled,
+		UpdatedAt: s.now(),
+	}
+	s.notificationPreferences[preferenceKey{arg.UserID, arg.Type}] = pref
+	return pref, nil
+}
+
+// deleteUserNotifications aplica las reglas ON DELETE de las tablas de notificaciones
+// al borrar un usuario. Debe llamarse con el mutex tomado.
+func (s *Store) deleteUserNotifications(id uuid.UUID) {
+	for notificationID, n := range s.notifications {
+		if n.UserID == id {
+			s.deleteNotification(notificationID)
+			continue
+		}
+		// ON DELETE SET NULL
+		if n.ActorID.Valid && n.ActorID.UUID == id {
+			n.ActorID = uuid.NullUUID{}
+			s.notifications[notificationID] = n
+		}
+	}
+	for key := range s.notificationActors {
+		if key.to == id {
+			delete(s.notificationActors, key)
+		}
+	}
+	for key := range s.notificationPreferences {
+		if key.userID == id {
+			delete(s.notificationPreferences, key)
+		}
+	}
+}
+
+// deleteNotification borra la notificación y sus actores. Debe llamarse con el mutex tomado.
+func (s *Store) deleteNotification(id uuid.UUID) {
+	delete(s.notifications, id)
+	for key := range s.notificationActors {
+		if key.from == id {
+			delete(s.notificationActors, key)
+		}
+	}
+}
+
+// unlinkChirpNotifications deja sin chirp a sus notificaciones (ON DELETE SET NULL).
+// Debe llamarse con el mutex tomado.
+func (s *Store) unlinkChirpNotifications(chirpID uuid.UUID) {
+	for id, n := range s.notifications {
+		if n.ChirpID.Valid && n.ChirpID.UUID == chirpID {
+			n.ChirpID = uuid.NullUUID{}
+			s.notifications[id] = n
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package memstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// LikeChirp devuelve 0 si el like ya existía (ON CONFLICT DO NOTHING).
+func (s *Store) LikeChirp(ctx context.Context, arg database.LikeChirpParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return 0, foreignKeyViolation("likes_user_id_fkey")
+	}
+	if _, ok := s.chirps[arg.ChirpID]; !ok {
+		return 0, foreignKeyViolation("likes_chirp_id_fkey")
+	}
+	key := pair{arg.UserID, arg.ChirpID}
+	if _, ok := s.likes[key]; ok {
+		return 0, nil
+	}
+	s.likes[key] = database.Like{UserID: arg.UserID, ChirpID: arg.ChirpID, CreatedAt: s.now()}
+	return 1, nil
+}
+
+func (s *Store) UnlikeChirp(ctx context.Context, arg database.UnlikeChirpParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.likes, pair{arg.UserID, arg.ChirpID})
+	return nil
+}
+
+func (s *Store) ListLikesByUser(ctx context.Context, userID uuid.UUID) ([]database.Like, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var likes []database.Like
+	for key, like := range s.likes {
+		if key.from == userID {
+			likes = append(likes, like)
+		}
+	}
+	sortByCreatedAt(likes, func(l database.Like) time.Time { return l.CreatedAt }, true)
+	return likes, nil
+}
+
+// FollowUser devuelve 0 si el follow ya existía (ON CONFLICT DO NOTHING).
+func (s *Store) FollowUser(ctx context.Context, arg database.FollowUserParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := pair{arg.FollowerID, arg.FollowedID}
+	if err := s.checkPair(key, "follows"); err != nil {
+		return 0, err
+	}
+	if _, ok := s.follows[key]; ok {
+		return 0, nil
+	}
+	s.follows[key] = database.Follow{FollowerID: arg.FollowerID, FollowedID: arg.FollowedID, CreatedAt: s.now()}
+	return 1, nil
+}
+
+func (s *Store) UnfollowUser(ctx context.Context, arg database.UnfollowUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.follows, pair{arg.FollowerID, arg.FollowedID})
+	return nil
+}
+
+// DeleteFollowsBetween borra los follows entre los dos usuarios, en ambos sentidos.
+func (s *Store) DeleteFollowsBetween(ctx context.Context, arg database.DeleteFollowsBetweenParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.follows, pair{arg.UserA, arg.UserB})
+	delete(s.follows, pair{arg.UserB, arg.UserA})
+	return nil
+}
+
+func (s *Store) ListFollowing(ctx context.Context, followerID uuid.UUID) ([]database.Follow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var follows []database.Follow
+	for key, follow := range s.follows {
+		if key.from == followerID {
+			follows = append(follows, follow)
+		}
+	}
+	sortByCreatedAt(follows, func(f database.Follow) time.Time { return f.CreatedAt }, true)
+	return follows, nil
+}
+
+func (s *Store) ListFollowers(ctx context.Context, followedID uuid.UUID) ([]database.Follow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var follows []database.Follow
+	for key, follow := range s.follows {
+		if key.to == followedID {
+			follows = append(follows, follow)
+		}
+	}
+	sortByCreatedAt(follows, func(f database.Follow) time.Time { return f.CreatedAt }, true)
+	return follows, nil
+}
@@ -0,0 +1,131 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (database.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.RefreshToken{}, foreignKeyViolation("refresh_tokens_user_id_fkey")
+	}
+	if _, ok := s.refreshTokens[arg.Token]; ok {
+		return database.RefreshToken{}, uniqueViolation("refresh_tokens_pkey")
+	}
+	now := s.now()
+	rt := database.RefreshToken{
+		Token:     arg.Token,
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    arg.UserID,
+		ExpiresAt: arg.ExpiresAt,
+	}
+	s.refreshTokens[rt.Token] = rt
+	return rt, nil
+}
+
+// GetUserFromRefreshToken devuelve sql.ErrNoRows si el token no existe, expiró o fue revocado.
+func (s *Store) GetUserFromRefreshToken(ctx context.Context, token string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rt, ok := s.refreshTokens[token]
+	if !ok || rt.RevokedAt.Valid || !rt.ExpiresAt.After(s.now()) {
+		return database.User{}, sql.ErrNoRows
+	}
+	return s.users[rt.UserID], nil
+}
+
+func (s *Store) ListRefreshTokensForUser(ctx context.Context, userID uuid.UUID) ([]database.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tokens []database.RefreshToken
+	for _, rt := range s.refreshTokens {
+		if rt.UserID == userID {
+			tokens = append(tokens, rt)
+		}
+	}
+	sortByCreatedAt(tokens, func(rt database.RefreshToken) time.Time { return rt.CreatedAt }, false)
+	return tokens, nil
+}
+
+func (s *Store) RevokeRefreshToken(ctx context.Context, token string) (database.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rt, ok := s.refreshTokens[token]
+	if !ok {
+		return database.RefreshToken{}, sql.ErrNoRows
+	}
+	rt.RevokedAt = s.nullNow()
+	rt.UpdatedAt = rt.RevokedAt.Time
+	s.refreshTokens[token] = rt
+	return rt, nil
+}
+
+func (s *Store) RevokeAllRefreshTokensForUser(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.nullNow()
+	for token, rt := range s.refreshTokens {
+		if rt.UserID == userID && !rt.RevokedAt.Valid {
+			rt.RevokedAt = now
+			rt.UpdatedAt = now.Time
+			s.refreshTokens[token] = rt
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateEmailVerification(ctx context.Context, arg database.CreateEmailVerificationParams) (database.EmailVerification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.EmailVerification{}, foreignKeyViolation("email_verifications_user_id_fkey")
+	}
+	if _, ok := s.emailVerifications[arg.Token]; ok {
+		return database.EmailVerification{}, uniqueViolation("email_verifications_pkey")
+	}
+	ev := database.EmailVerification{
+		Token:     arg.Token,
+		CreatedAt: s.now(),
+		UserID:    arg.UserID,
+		Email:     arg.Email,
+		ExpiresAt: arg.ExpiresAt,
+	}
+	s.emailVerifications[ev.Token] = ev
+	return ev, nil
+}
+
+// GetPendingEmailVerification devuelve sql.ErrNoRows si el token no existe, expiró o ya se usó.
+func (s *Store) GetPendingEmailVerification(ctx context.Context, token string) (database.EmailVerification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ev, ok := s.emailVerifications[token]
+	if !ok || ev.UsedAt.Valid || !ev.ExpiresAt.After(s.now()) {
+		return database.EmailVerification{}, sql.ErrNoRows
+	}
+	return ev, nil
+}
+
+func (s *Store) MarkEmailVerificationUsed(ctx context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ev, ok := s.emailVerifications[token]; ok {
+		ev.UsedAt = s.nullNow()
+		s.emailVerifications[token] = ev
+	}
+	return nil
+}
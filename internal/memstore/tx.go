@@ -0,0 +1,91 @@
+package memstore
+
+import (
+	"context"
+	"maps"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+)
+
+// InTx ejecuta fn de forma exclusiva respecto a otras transacciones. Si fn falla se
+// restaura el estado previo, como haría un ROLLBACK. Es un modelo simple pensado para
+// tests: las escrituras fuera de transacción hechas mientras fn corre también se
+// pierden si hay rollback.
+func (s *Store) InTx(ctx context.Context, fn func(q service.Queries) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	saved := s.snapshot()
+	if err := fn(s); err != nil {
+		s.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// tables es una copia de todas las tablas del Store.
+type tables struct {
+	users              map[uuid.UUID]database.User
+	chirps             map[uuid.UUID]database.Chirp
+	refreshTokens      map[string]database.RefreshToken
+	emailVerifications map[string]database.EmailVerification
+	blocks             map[pair]database.Block
+	mutes              map[pair]database.Mute
+	likes              map[pair]database.Like
+	follows            map[pair]database.Follow
+	reports            map[uuid.UUID]database.Report
+	auditLogs          map[uuid.UUID]database.AdminAuditLog
+	subscriptionEvents map[uuid.UUID]database.SubscriptionEvent
+	idempotencyKeys    map[idempotencyKey]database.IdempotencyKey
+
+	notifications           map[uuid.UUID]database.Notification
+	notificationActors      map[pair]database.NotificationActor
+	notificationPreferences map[preferenceKey]database.NotificationPreference
+}
+
+func (s *Store) snapshot() tables {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return tables{
+		users:              maps.Clone(s.users),
+		chirps:             maps.Clone(s.chirps),
+		refreshTokens:      maps.Clone(s.refreshTokens),
+		emailVerifications: maps.Clone(s.emailVerifications),
+		blocks:             maps.Clone(s.blocks),
+		mutes:              maps.Clone(s.mutes),
+		likes:              maps.Clone(s.likes),
+		follows:            maps.Clone(s.follows),
+		reports:            maps.Clone(s.reports),
+		auditLogs:          maps.Clone(s.auditLogs),
+		subscriptionEvents: maps.Clone(s.subscriptionEvents),
+		idempotencyKeys:    maps.Clone(s.idempotencyKeys),
+
+		notifications:           maps.Clone(s.notifications),
+		notificationActors:      maps.Clone(s.notificationActors),
+		notificationPreferences: maps.Clone(s.notificationPreferences),
+	}
+}
+
+func (s *Store) restore(t tables) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = t.users
+	s.chirps = t.chirps
+	s.refreshTokens = t.refreshTokens
+	s.emailVerifications = t.emailVerifications
+	s.blocks = t.blocks
+	s.mutes = t.mutes
+	s.likes = t.likes
+	s.follows = t.follows
+	s.reports = t.reports
+	s.auditLogs = t.auditLogs
+	s.subscriptionEvents = t.subscriptionEvents
+	s.idempotencyKeys = t.idempotencyKeys
+	s.notifications = t.notifications
+	s.notificationActors = t.notificationActors
+	s.notificationPreferences = t.notificationPreferences
+}
@@ -0,0 +1,230 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// roles válidos según el CHECK de la columna users.role
+var validRoles = map[string]bool{"user": true, "moderator": true, "admin": true}
+
+// checkUser valida las restricciones de la tabla users para una fila nueva o modificada.
+// Debe llamarse con el mutex tomado.
+func (s *Store) checkUser(user database.User) error {
+	if !validRoles[user.Role] {
+		return checkViolation("users_role_check")
+	}
+	for id, other := range s.users {
+		if id == user.ID {
+			continue
+		}
+		if strings.EqualFold(other.Email, user.Email) {
+			return uniqueViolation("users_email_lower_idx")
+		}
+		if user.Username.Valid && other.Username.Valid &&
+			strings.EqualFold(other.Username.String, user.Username.String) {
+			return uniqueViolation("users_username_lower_idx")
+		}
+	}
+	return nil
+}
+
+// updateUser aplica change al usuario id y guarda el resultado si cumple las restricciones.
+func (s *Store) updateUser(id uuid.UUID, change func(*database.User)) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	change(&user)
+	user.UpdatedAt = s.now()
+	if err := s.checkUser(user); err != nil {
+		return database.User{}, err
+	}
+	s.users[id] = user
+	return user, nil
+}
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	user := database.User{
+		ID:             uuid.New(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Email:          arg.Email,
+		HashedPassword: arg.HashedPassword,
+		Role:           "user",
+		Username:       arg.Username,
+		DisplayName:    arg.DisplayName,
+	}
+	if err := s.checkUser(user); err != nil {
+		return database.User{}, err
+	}
+	s.users[user.ID] = user
+	return user, nil
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+// GetUserByUsername no distingue mayúsculas e ignora las cuentas pendientes de eliminación.
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.Username.Valid && strings.EqualFold(user.Username.String, username) && !user.DeletedAt.Valid {
+			return user, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+// ListUsers filtra por email sin distinguir mayúsculas (ILIKE '%q%').
+func (s *Store) ListUsers(ctx context.Context, arg database.ListUsersParams) ([]database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := strings.ToLower(arg.Query)
+	var users []database.User
+	for _, user := range s.users {
+		if query == "" || strings.Contains(strings.ToLower(user.Email), query) {
+			users = append(users, user)
+		}
+	}
+	sortByCreatedAt(users, func(u database.User) time.Time { return u.CreatedAt }, false)
+	return paginate(users, arg.RowLimit, arg.RowOffset), nil
+}
+
+func (s *Store) UpdateUser(ctx context.Context, arg database.UpdateUserParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) {
+		u.Email = arg.Email
+		u.HashedPassword = arg.HashedPassword
+	})
+}
+
+func (s *Store) UpdateUserEmail(ctx context.Context, arg database.UpdateUserEmailParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) {
+		u.Email = arg.Email
+	})
+}
+
+func (s *Store) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) {
+		u.Username = arg.Username
+		u.DisplayName = arg.DisplayName
+		u.Bio = arg.Bio
+		u.AvatarUrl = arg.AvatarUrl
+	})
+}
+
+// PatchUser solo cambia los campos válidos (COALESCE en la consulta SQL).
+func (s *Store) PatchUser(ctx context.Context, arg database.PatchUserParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) {
+		if arg.HashedPassword.Valid {
+			u.HashedPassword = arg.HashedPassword.String
+		}
+		if arg.Username.Valid {
+			u.Username = arg.Username
+		}
+		if arg.DisplayName.Valid {
+			u.DisplayName = arg.DisplayName.String
+		}
+		if arg.Bio.Valid {
+			u.Bio = arg.Bio.String
+		}
+		if arg.AvatarUrl.Valid {
+			u.AvatarUrl = arg.AvatarUrl.String
+		}
+	})
+}
+
+func (s *Store) UpgradeToChirpyRed(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return s.updateUser(id, func(u *database.User) {
+		u.IsChirpyRed = true
+	})
+}
+
+func (s *Store) SetChirpyRed(ctx context.Context, arg database.SetChirpyRedParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) {
+		u.IsChirpyRed = arg.IsChirpyRed
+	})
+}
+
+func (s *Store) SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) {
+		u.Role = arg.Role
+	})
+}
+
+func (s *Store) SuspendUser(ctx context.Context, arg database.SuspendUserParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) {
+		u.SuspendedUntil = arg.SuspendedUntil
+	})
+}
+
+func (s *Store) BanUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return s.updateUser(id, func(u *database.User) {
+		u.BannedAt = s.nullNow()
+	})
+}
+
+func (s *Store) ReinstateUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return s.updateUser(id, func(u *database.User) {
+		u.SuspendedUntil = sql.NullTime{}
+		u.BannedAt = sql.NullTime{}
+	})
+}
+
+// SoftDeleteUser devuelve sql.ErrNoRows si la cuenta ya estaba pendiente de eliminación.
+func (s *Store) SoftDeleteUser(ctx context.Context, arg database.SoftDeleteUserParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok || user.DeletedAt.Valid {
+		return database.User{}, sql.ErrNoRows
+	}
+	user.DeletedAt = s.nullNow()
+	user.PurgeAfter = arg.PurgeAfter
+	user.UpdatedAt = user.DeletedAt.Time
+	s.users[arg.ID] = user
+	return user, nil
+}
+
+func (s *Store) CancelUserDeletion(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return s.updateUser(id, func(u *database.User) {
+		u.DeletedAt = sql.NullTime{}
+		u.PurgeAfter = sql.NullTime{}
+	})
+}
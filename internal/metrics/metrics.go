@@ -0,0 +1,121 @@
+// Package metrics define las métricas de Chirpy en formato Prometheus.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "chirpy"
+
+// Registry contiene todas las métricas de la aplicación. No se usa el registro global
+// de Prometheus para que solo se expongan las métricas que registramos aquí.
+var Registry = prometheus.NewRegistry()
+
+// Métricas HTTP
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+)
+
+// Métricas de negocio
+var (
+	ChirpsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "chirps_created_total",
+		Help:      "Chirps created.",
+	})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result (succeeded, failed).",
+	}, []string{"result"})
+
+	WebhooksProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhooks_processed_total",
+		Help:      "Polka webhooks processed by event.",
+	}, []string{"event"})
+
+	RefreshTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "refresh_tokens_total",
+		Help:      "Refresh token operations by action (issued, used, revoked).",
+	}, []string{"action"})
+)
+
+// Métricas del caché de lectura
+var CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "cache_requests_total",
+	Help:      "Read-through cache lookups by entity (chirp, user) and result (hit, miss, bypass).",
+}, []string{"entity", "result"})
+
+// Métricas del rate limiting
+var RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "rate_limited_total",
+	Help:      "Requests rejected with 429 by rate limit policy.",
+}, []string{"policy"})
+
+// Valores de las etiquetas de negocio
+const (
+	LoginSucceeded = "succeeded"
+	LoginFailed    = "failed"
+
+	RefreshTokenIssued  = "issued"
+	RefreshTokenUsed    = "used"
+	RefreshTokenRevoked = "revoked"
+
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+	// CacheBypass cuenta las lecturas que pidieron ir a la base de datos
+	CacheBypass = "bypass"
+)
+
+func init() {
+	Registry.MustRegister(
+		HTTPRequests,
+		HTTPDuration,
+		HTTPInFlight,
+		ChirpsCreated,
+		Logins,
+		WebhooksProcessed,
+		RefreshTokens,
+		CacheRequests,
+		RateLimited,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB expone las estadísticas del pool de conexiones (sql.DB.Stats()).
+func RegisterDB(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "chirpy"))
+}
+
+// Handler sirve las métricas en el formato de texto de Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
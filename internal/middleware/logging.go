@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/google/uuid"
+)
+
+// requestInfo lo completan los handlers durante la petición (por ejemplo, el usuario autenticado).
+type requestInfo struct {
+	userID uuid.UUID
+}
+
+type requestInfoKey struct{}
+
+// SetUserID asocia el usuario autenticado a la petición para que aparezca en el access log.
+// No hace nada si la petición no pasó por AccessLog.
+func SetUserID(ctx context.Context, userID uuid.UUID) {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		info.userID = userID
+	}
+}
+
+// AccessLog registra una línea por petición con método, ruta, status, latencia y usuario.
+// Entre AccessLog y el mux no puede haber middlewares que clonen la petición (r.WithContext),
+// porque el patrón de ruta lo asigna el mux sobre la misma *http.Request.
+func AccessLog(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			info := &requestInfo{}
+			r = r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info))
+			rec := wrapResponseWriter(w)
+
+			next.ServeHTTP(rec, r)
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := r.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+
+			attrs := []slog.Attr{
+				slog.String("request_id", api.RequestIDFromContext(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int("bytes", rec.bytes),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("remote_addr", r.RemoteAddr),
+			}
+			if info.userID != uuid.Nil {
+				attrs = append(attrs, slog.String("user_id", info.userID.String()))
+			}
+
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(r.Context(), level, "request", attrs...)
+		})
+	}
+}
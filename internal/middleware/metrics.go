@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+)
+
+// Metrics cuenta las peticiones y su latencia por patrón de ruta.
+// Igual que AccessLog, no puede haber middlewares que clonen la petición entre este y el mux.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		metrics.HTTPInFlight.Inc()
+		defer metrics.HTTPInFlight.Dec()
+
+		rec := wrapResponseWriter(w)
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		// Las rutas sin patrón se agrupan para no disparar la cardinalidad con paths arbitrarios
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		metrics.HTTPDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+)
+
+// Recover captura los panics de los handlers, los registra con su stack y
+// responde 500 con un problem+json en lugar de cortar la conexión.
+func Recover(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := wrapResponseWriter(w)
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				// http.ErrAbortHandler es la forma estándar de abortar una respuesta a propósito
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				logger.ErrorContext(r.Context(), "panic in handler",
+					slog.String("request_id", api.RequestIDFromContext(r.Context())),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Any("panic", p),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				// Si ya se enviaron los headers solo queda cerrar la respuesta
+				if rec.status != 0 {
+					return
+				}
+				api.RespondWithError(rec, r, http.StatusInternalServerError, api.CodeInternalError, nil)
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Atributos cuyo valor nunca debe llegar a los logs
+var sensitiveKeys = map[string]struct{}{
+	"authorization": {},
+	"password":      {},
+	"token":         {},
+	"refresh_token": {},
+	"api_key":       {},
+	"email":         {},
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	schemePattern = regexp.MustCompile(`(?i)\b(Bearer|ApiKey)\s+\S+`)
+	// Los refresh tokens y tokens de verificación son 32 bytes en hex; los request IDs son más cortos
+	hexTokenPattern = regexp.MustCompile(`\b[0-9a-fA-F]{48,}\b`)
+)
+
+// NewLogger crea un logger JSON que redacta emails y tokens de todos los mensajes y atributos.
+func NewLogger(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	}))
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if _, ok := sensitiveKeys[strings.ToLower(a.Key)]; ok {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return a
+}
+
+// Redact reemplaza emails y tokens dentro de un texto libre.
+func Redact(s string) string {
+	s = emailPattern.ReplaceAllString(s, "[EMAIL]")
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = schemePattern.ReplaceAllString(s, "$1 "+redacted)
+	s = hexTokenPattern.ReplaceAllString(s, redacted)
+	return s
+}
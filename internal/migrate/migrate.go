@@ -0,0 +1,148 @@
+// Package migrate aplica con goose las migraciones incrustadas: sql/schema para Postgres
+// y sql/sqlite/schema para SQLite.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/sql/schema"
+	sqliteschema "github.com/amadrigalIstmo/Chirpy-project/sql/sqlite/schema"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// ErrSchemaBehind indica que la base de datos tiene migraciones pendientes.
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// Commands son los subcomandos aceptados por Run.
+var Commands = []string{"up", "down", "status", "redo"}
+
+// Dialectos soportados. Coinciden con el nombre del driver de database/sql.
+const (
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite3"
+)
+
+// NewProvider crea un provider de goose sobre las migraciones incrustadas del dialecto.
+// En Postgres las operaciones que modifican el esquema toman un advisory lock,
+// así varias réplicas que arrancan a la vez no aplican la misma migración.
+// SQLite es solo para desarrollo local y no necesita lock.
+func NewProvider(db *sql.DB, dialect string) (*goose.Provider, error) {
+	options := []goose.ProviderOption{
+		goose.WithDisableGlobalRegistry(true),
+		goose.WithSlog(slog.Default()),
+	}
+
+	switch dialect {
+	case DialectPostgres:
+		locker, err := lock.NewPostgresSessionLocker()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, goose.WithSessionLocker(locker))
+		return goose.NewProvider(goose.DialectPostgres, db, schema.FS, options...)
+
+	case DialectSQLite:
+		return goose.NewProvider(goose.DialectSQLite3, db, sqliteschema.FS, options...)
+
+	default:
+		return nil, fmt.Errorf("unsupported migration dialect %q", dialect)
+	}
+}
+
+// LatestVersion devuelve la versión de la última migración incrustada.
+func LatestVersion(p *goose.Provider) int64 {
+	sources := p.ListSources()
+	if len(sources) == 0 {
+		return 0
+	}
+	return sources[len(sources)-1].Version
+}
+
+// Run ejecuta un subcomando de migración y escribe el resultado en out.
+func Run(ctx context.Context, p *goose.Provider, command string, out io.Writer) error {
+	switch command {
+	case "up":
+		results, err := p.Up(ctx)
+		printResults(out, results)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return nil
+
+	case "down":
+		result, err := p.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			return errors.New("no migrations to roll back")
+		}
+		printResults(out, []*goose.MigrationResult{result})
+		return err
+
+	case "redo":
+		result, err := p.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			return errors.New("no migrations to redo")
+		}
+		printResults(out, []*goose.MigrationResult{result})
+		if err != nil {
+			return err
+		}
+		result, err = p.UpByOne(ctx)
+		printResults(out, []*goose.MigrationResult{result})
+		return err
+
+	case "status":
+		statuses, err := p.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if !s.AppliedAt.IsZero() {
+				appliedAt = s.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Source.Version, s.State, appliedAt, s.Source.Path)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected one of %v)", command, Commands)
+	}
+}
+
+// EnsureCurrent devuelve ErrSchemaBehind si hay migraciones sin aplicar.
+func EnsureCurrent(ctx context.Context, p *goose.Provider) error {
+	current, target, err := p.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("couldn't read schema version: %w", err)
+	}
+	if current < target {
+		return fmt.Errorf("%w: at version %d, latest is %d (run \"chirpy migrate up\")", ErrSchemaBehind, current, target)
+	}
+	return nil
+}
+
+func printResults(out io.Writer, results []*goose.MigrationResult) {
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		status := "OK"
+		if r.Error != nil {
+			status = "FAILED"
+		}
+		fmt.Fprintf(out, "%-4s %-6s %s (%s)\n", r.Direction, status, r.Source.Path, r.Duration.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,72 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/sqlitedb"
+
+	_ "github.com/lib/pq"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	// sql.Open no conecta: alcanza para leer las migraciones incrustadas
+	connections := map[string]string{
+		DialectPostgres: "postgres://localhost/chirpy?sslmode=disable",
+		DialectSQLite:   "file::memory:",
+	}
+
+	for dialect, dsn := range connections {
+		t.Run(dialect, func(t *testing.T) {
+			db, err := sql.Open(dialect, dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			p, err := NewProvider(db, dialect)
+			if err != nil {
+				t.Fatalf("NewProvider: %v", err)
+			}
+
+			sources := p.ListSources()
+			if len(sources) == 0 {
+				t.Fatal("no embedded migrations")
+			}
+			for i, source := range sources {
+				if source.Version != int64(i+1) {
+					t.Errorf("migration %s has version %d, want %d (versions must be contiguous)", source.Path, source.Version, i+1)
+				}
+			}
+			if got := LatestVersion(p); got != int64(len(sources)) {
+				t.Errorf("LatestVersion = %d, want %d", got, len(sources))
+			}
+		})
+	}
+}
+
+func TestSQLiteMigrationsApply(t *testing.T) {
+	db, err := sqlitedb.Open("sqlite://:memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	p, err := NewProvider(db, DialectSQLite)
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := Run(ctx, p, "up", io.Discard); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if err := EnsureCurrent(ctx, p); err != nil {
+		t.Errorf("EnsureCurrent after up: %v", err)
+	}
+	if err := Run(ctx, p, "redo", io.Discard); err != nil {
+		t.Errorf("migrate redo: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval es cada cuánto MemoryStore descarta los buckets que ya se llenaron:
+// un bucket lleno equivale a uno que no existe.
+const sweepInterval = time.Minute
+
+// MemoryStore guarda los buckets en memoria del proceso. Con varias réplicas cada una
+// tiene sus propios buckets, así que la cuota efectiva se multiplica por las réplicas.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// NewMemoryStore crea un MemoryStore vacío.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updated: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = timeToRefill(limit, 1-b.tokens)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = timeToRefill(limit, float64(limit.Requests)-b.tokens)
+	return result, nil
+}
+
+// refill suma los tokens recuperados desde la última petición, sin pasar de la capacidad
+// (que puede haber bajado si el usuario perdió Chirpy Red).
+func (b *bucket) refill(now time.Time) {
+	elapsed := max(now.Sub(b.updated).Seconds(), 0)
+	b.tokens = math.Min(float64(b.limit.Requests), b.tokens+elapsed*b.limit.rate())
+	b.updated = now
+}
+
+// sweep debe llamarse con el mutex tomado.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Requests) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Len devuelve cuántos buckets hay guardados.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+// timeToRefill devuelve cuánto se tarda en recuperar tokens con limit.
+func timeToRefill(limit Limit, tokens float64) time.Duration {
+	return time.Duration(tokens / limit.rate() * float64(time.Second))
+}
@@ -0,0 +1,115 @@
+// Package ratelimit limita las peticiones por cliente con token buckets.
+//
+// Cada política (Policy) tiene su propio bucket por cliente: se llena con Limit.Requests
+// tokens cada Limit.Period y cada petición consume uno. El estado vive en un Store;
+// MemoryStore sirve para una réplica y, con varias, hace falta un Store compartido.
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/api"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+)
+
+// Limit admite Requests peticiones por Period, con ráfagas de hasta Requests.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// PerMinute devuelve un Limit de n peticiones por minuto.
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Period: time.Minute}
+}
+
+// rate devuelve cuántos tokens se recuperan por segundo.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Policy es el límite de un grupo de rutas. Premium se aplica a los usuarios con
+// Chirpy Red; si es cero, se usa Limit para todos.
+type Policy struct {
+	Name    string
+	Limit   Limit
+	Premium Limit
+}
+
+// Result es el estado del bucket después de intentar consumir un token.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset es el tiempo hasta que el bucket vuelve a estar lleno.
+	Reset time.Duration
+	// RetryAfter es el tiempo hasta el próximo token; solo es distinto de cero si !Allowed.
+	RetryAfter time.Duration
+}
+
+// Store guarda los buckets. Take debe ser atómica por clave: en un Store compartido
+// (por ejemplo Redis con un script Lua) dos réplicas no pueden consumir el mismo token.
+type Store interface {
+	// Take consume un token del bucket key, creándolo lleno si no existe.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Identify devuelve la clave del cliente (usuario o IP) y si tiene Chirpy Red.
+type Identify func(r *http.Request) (key string, premium bool)
+
+// Limiter aplica políticas a handlers HTTP.
+type Limiter struct {
+	store    Store
+	identify Identify
+}
+
+// NewLimiter crea un Limiter que guarda los buckets en store y distingue a los
+// clientes con identify.
+func NewLimiter(store Store, identify Identify) *Limiter {
+	return &Limiter{store: store, identify: identify}
+}
+
+// Handler aplica policy a next. Todas las respuestas llevan los headers RateLimit-*;
+// si el cliente agotó su cuota se responde 429 con Retry-After sin llamar a next.
+// Si el Store falla, la petición pasa: es preferible no limitar a dejar de servir.
+func (l *Limiter) Handler(policy Policy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, premium := l.identify(r)
+		limit := policy.Limit
+		if premium && policy.Premium.Requests > 0 {
+			limit = policy.Premium
+		}
+
+		result, err := l.store.Take(r.Context(), policy.Name+":"+key, limit)
+		if err != nil {
+			slog.WarnContext(r.Context(), "rate limit store failed; request not limited",
+				"policy", policy.Name, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+seconds(limit.Period))
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", seconds(result.Reset))
+
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(policy.Name).Inc()
+			header.Set("Retry-After", seconds(result.RetryAfter))
+			api.RespondWithError(w, r, http.StatusTooManyRequests, api.CodeRateLimited, nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// seconds redondea d hacia arriba a segundos enteros, como piden los headers.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreRefills(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Period: time.Minute}
+	ctx := context.Background()
+
+	take := func() Result {
+		t.Helper()
+		result, err := s.Take(ctx, "ip:1", limit)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		return result
+	}
+
+	if r := take(); !r.Allowed || r.Remaining != 1 {
+		t.Fatalf("first take = %+v, want allowed with 1 remaining", r)
+	}
+	if r := take(); !r.Allowed || r.Remaining != 0 || r.Reset != time.Minute {
+		t.Fatalf("second take = %+v, want allowed with 0 remaining and a 1m reset", r)
+	}
+	r := take()
+	if r.Allowed || r.RetryAfter != 30*time.Second {
+		t.Fatalf("third take = %+v, want denied with a 30s retry", r)
+	}
+
+	// Medio minuto después se recupera un token
+	now = now.Add(30 * time.Second)
+	if r := take(); !r.Allowed || r.Remaining != 0 {
+		t.Fatalf("take after refill = %+v, want allowed", r)
+	}
+
+	// Otra clave tiene su propio bucket
+	if r, _ := s.Take(ctx, "ip:2", limit); !r.Allowed {
+		t.Fatalf("take for another key = %+v, want allowed", r)
+	}
+
+	// Los buckets llenos se descartan
+	now = now.Add(2 * sweepInterval)
+	take()
+	if got := s.Len(); got != 1 {
+		t.Errorf("Len() after sweep = %d, want 1", got)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("store is down")
+}
+
+func TestLimiterHandler(t *testing.T) {
+	identify := func(r *http.Request) (string, bool) {
+		return r.Header.Get("X-Client"), r.Header.Get("X-Premium") == "yes"
+	}
+	policy := Policy{Name: "test", Limit: PerMinute(1), Premium: PerMinute(2)}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	serve := func(h http.Handler, client, premium string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.Header.Set("X-Client", client)
+		r.Header.Set("X-Premium", premium)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	h := NewLimiter(NewMemoryStore(), identify).Handler(policy, ok)
+	w := serve(h, "a", "no")
+	if w.Code != http.StatusNoContent || w.Header().Get("RateLimit-Policy") != "1;w=60" {
+		t.Fatalf("first request = %d, RateLimit-Policy %q", w.Code, w.Header().Get("RateLimit-Policy"))
+	}
+	if w := serve(h, "a", "no"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" {
+		t.Fatalf("second request = %d, Retry-After %q, want 429 and 60", w.Code, w.Header().Get("Retry-After"))
+	}
+	for i := 0; i < 2; i++ {
+		if w := serve(h, "b", "yes"); w.Code != http.StatusNoContent {
+			t.Fatalf("premium request %d = %d, want %d", i+1, w.Code, http.StatusNoContent)
+		}
+	}
+
+	// Si el store falla, la petición pasa
+	h = NewLimiter(failingStore{}, identify).Handler(policy, ok)
+	if w := serve(h, "a", "no"); w.Code != http.StatusNoContent {
+		t.Errorf("request with a failing store = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
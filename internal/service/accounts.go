@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// Roles disponibles para los usuarios
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// roleRank permite comparar roles: un admin puede hacer todo lo que hace un moderador
+var roleRank = map[string]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+// ValidRole indica si role es uno de los roles existentes.
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// HasRole indica si user tiene al menos el rol minRole.
+func HasRole(user database.User, minRole string) bool {
+	return roleRank[user.Role] >= roleRank[minRole]
+}
+
+// CheckAccount devuelve un error si la cuenta está eliminada, baneada o suspendida.
+func CheckAccount(user database.User) error {
+	if user.DeletedAt.Valid {
+		return ErrAccountPendingDeletion
+	}
+	if user.BannedAt.Valid {
+		return ErrAccountBanned
+	}
+	if user.SuspendedUntil.Valid && user.SuspendedUntil.Time.After(time.Now().UTC()) {
+		return ErrAccountSuspended
+	}
+	return nil
+}
+
+// NormalizeEmail devuelve el email tal como se guarda: sin espacios y en minúsculas.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Session es el resultado de iniciar sesión.
+type Session struct {
+	User         database.User
+	AccessToken  string
+	RefreshToken string
+}
+
+// Login comprueba las credenciales y abre una sesión. Iniciar sesión durante el periodo
+// de gracia cancela la eliminación de la cuenta; las cuentas baneadas o suspendidas se rechazan.
+func (s *Service) Login(ctx context.Context, email, password string) (Session, error) {
+	// bcrypt es lento a propósito: se comprueba antes de abrir la transacción
+	user, err := s.store.GetUserByEmail(ctx, NormalizeEmail(email))
+	if database.IsNotFound(err) {
+		return Session{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return Session{}, err
+	}
+	if err := auth.CheckPasswordHash(password, user.HashedPassword); err != nil {
+		return Session{}, ErrInvalidCredentials
+	}
+
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return Session{}, err
+	}
+
+	err = s.inTx(ctx, func(q Queries) error {
+		// Releer al usuario dentro de la transacción: pudo cambiar o purgarse entretanto
+		user, err = q.GetUserByID(ctx, user.ID)
+		if database.IsNotFound(err) {
+			return ErrInvalidCredentials
+		}
+		if err != nil {
+			return err
+		}
+
+		if user.DeletedAt.Valid {
+			user, err = q.CancelUserDeletion(ctx, user.ID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := CheckAccount(user); err != nil {
+			return err
+		}
+
+		_, err = q.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+			UserID:    user.ID,
+			Token:     refreshToken,
+			ExpiresAt: time.Now().UTC().Add(s.refreshTokenTTL),
+		})
+		return err
+	})
+	if err != nil {
+		return Session{}, err
+	}
+
+	accessToken, err := auth.MakeJWT(user.ID, s.jwtSecret, s.accessTokenTTL)
+	if err != nil {
+		return Session{}, err
+	}
+
+	return Session{User: user, AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// DeleteAccount programa la eliminación de la cuenta tras re-confirmar la contraseña y
+// cierra todas sus sesiones. La cuenta se purga al terminar el periodo de gracia.
+func (s *Service) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (database.User, error) {
+	// Sin caché: el hash pudo cambiar en otra réplica
+	user, err := s.store.GetUserByID(FreshReads(ctx), userID)
+	if err != nil {
+		return database.User{}, notFound(err, ErrUserNotFound)
+	}
+	if err := auth.CheckPasswordHash(password, user.HashedPassword); err != nil {
+		return database.User{}, ErrIncorrectPassword
+	}
+
+	var deleted database.User
+	err = s.inTx(ctx, func(q Queries) error {
+		var err error
+		deleted, err = q.SoftDeleteUser(ctx, database.SoftDeleteUserParams{
+			ID:         user.ID,
+			PurgeAfter: sql.NullTime{Time: time.Now().UTC().Add(s.deletionGracePeriod), Valid: true},
+		})
+		if database.IsNotFound(err) {
+			return ErrAccountAlreadyDeleted
+		}
+		if err != nil {
+			return err
+		}
+		return q.RevokeAllRefreshTokensForUser(ctx, user.ID)
+	})
+	return deleted, err
+}
+
+// UserChanges son los cambios de UpdateUser. Los campos nil no cambian; los valores ya
+// vienen validados y normalizados por quien llama.
+type UserChanges struct {
+	Email           *string
+	Password        *string
+	CurrentPassword string
+	Username        *string
+	DisplayName     *string
+	Bio             *string
+	AvatarURL       *string
+}
+
+// UpdateUser aplica changes al usuario. Cambiar la contraseña exige la contraseña
+// actual. El nuevo email no se aplica: se guarda una verificación pendiente que se
+// devuelve para enviarla al nuevo correo, o una vacía si el email no cambia.
+func (s *Service) UpdateUser(ctx context.Context, userID uuid.UUID, changes UserChanges) (database.User, database.EmailVerification, error) {
+	params := database.PatchUserParams{ID: userID}
+	if changes.Password != nil {
+		hashedPassword, err := auth.HashPassword(*changes.Password)
+		if err != nil {
+			return database.User{}, database.EmailVerification{}, err
+		}
+		params.HashedPassword = sql.NullString{String: hashedPassword, Valid: true}
+	}
+	params.Username = nullString(changes.Username)
+	params.DisplayName = nullString(changes.DisplayName)
+	params.Bio = nullString(changes.Bio)
+	params.AvatarUrl = nullString(changes.AvatarURL)
+
+	var user database.User
+	var verification database.EmailVerification
+	err := s.inTx(ctx, func(q Queries) error {
+		verification = database.EmailVerification{}
+		current, err := q.GetUserByID(ctx, userID)
+		if err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+		if changes.Password != nil {
+			if err := auth.CheckPasswordHash(changes.CurrentPassword, current.HashedPassword); err != nil {
+				return ErrIncorrectPassword
+			}
+		}
+
+		newEmail := ""
+		if changes.Email != nil && NormalizeEmail(*changes.Email) != current.Email {
+			newEmail = NormalizeEmail(*changes.Email)
+			_, err := q.GetUserByEmail(ctx, newEmail)
+			if err == nil {
+				return ErrEmailInUse
+			}
+			if !database.IsNotFound(err) {
+				return err
+			}
+		}
+
+		user, err = q.PatchUser(ctx, params)
+		if database.IsConflict(err) {
+			return ErrUsernameTaken
+		}
+		if err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+		if newEmail == "" {
+			return nil
+		}
+
+		token, err := auth.MakeVerificationToken()
+		if err != nil {
+			return err
+		}
+		verification, err = q.CreateEmailVerification(ctx, database.CreateEmailVerificationParams{
+			Token:     token,
+			UserID:    user.ID,
+			Email:     newEmail,
+			ExpiresAt: time.Now().UTC().Add(s.emailVerificationTTL),
+		})
+		return err
+	})
+	if err != nil {
+		return database.User{}, database.EmailVerification{}, err
+	}
+	return user, verification, nil
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// VerifyEmail aplica el cambio de email pendiente del token y lo marca como usado.
+func (s *Service) VerifyEmail(ctx context.Context, token string) (database.User, error) {
+	var user database.User
+	err := s.inTx(ctx, func(q Queries) error {
+		verification, err := q.GetPendingEmailVerification(ctx, token)
+		if database.IsNotFound(err) {
+			return ErrInvalidVerificationToken
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err = q.UpdateUserEmail(ctx, database.UpdateUserEmailParams{
+			ID:    verification.UserID,
+			Email: verification.Email,
+		})
+		if database.IsConflict(err) {
+			return ErrEmailInUse
+		}
+		if err != nil {
+			return err
+		}
+
+		return q.MarkEmailVerificationUsed(ctx, verification.Token)
+	})
+	return user, err
+}
+
+// UpgradeToChirpyRed activa Chirpy Red por un evento de pago y lo guarda en el
+// historial de suscripción del usuario.
+func (s *Service) UpgradeToChirpyRed(ctx context.Context, userID uuid.UUID, event, source string) error {
+	return s.inTx(ctx, func(q Queries) error {
+		if _, err := q.UpgradeToChirpyRed(ctx, userID); err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+		_, err := q.CreateSubscriptionEvent(ctx, database.CreateSubscriptionEventParams{
+			UserID: userID,
+			Event:  event,
+			Source: source,
+		})
+		return err
+	})
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/google/uuid"
+)
+
+// CreateChirp publica un chirp de authorID y devuelve también al autor. El cuerpo se
+// valida y se censura; no se puede publicar con la cuenta restringida ni mencionar a
+// quien te bloqueó. Si replyToID es válido, el chirp responde a ese chirp y se avisa
+// a su autor, salvo que el autor te haya bloqueado.
+func (s *Service) CreateChirp(ctx context.Context, authorID uuid.UUID, body string, replyToID uuid.NullUUID) (database.Chirp, database.User, error) {
+	cleaned, err := CleanChirp(body, s.maxChirpLength)
+	if err != nil {
+		return database.Chirp{}, database.User{}, err
+	}
+
+	var chirp database.Chirp
+	var author database.User
+	var notifications []database.Notification
+	err = s.inTx(ctx, func(q Queries) error {
+		notifications = nil
+		var err error
+		author, err = q.GetUserByID(ctx, authorID)
+		if err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+		if err := CheckAccount(author); err != nil {
+			return err
+		}
+
+		var parent database.Chirp
+		if replyToID.Valid {
+			parent, err = publicChirp(ctx, q, replyToID.UUID)
+			if err != nil {
+				return err
+			}
+			if err := checkNotBlockedBy(ctx, q, parent.UserID, author.ID); err != nil {
+				return err
+			}
+		}
+
+		var mentioned []database.User
+		for _, username := range ExtractMentions(cleaned) {
+			user, err := q.GetUserByUsername(ctx, username)
+			if database.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			blocked, err := isBlockedBy(ctx, q, user.ID, author.ID)
+			if err != nil {
+				return err
+			}
+			if blocked {
+				return ErrMentionBlocked
+			}
+			mentioned = append(mentioned, user)
+		}
+
+		chirp, err = q.CreateChirp(ctx, database.CreateChirpParams{
+			Body:      cleaned,
+			UserID:    author.ID,
+			ReplyToID: replyToID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if replyToID.Valid {
+			n, ok, err := s.notify(ctx, q, notification{
+				userID:   parent.UserID,
+				actorID:  author.ID,
+				typ:      NotificationReply,
+				groupKey: NotificationReply + ":" + parent.ID.String(),
+				chirpID:  uuid.NullUUID{UUID: chirp.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if ok {
+				notifications = append(notifications, n)
+			}
+		}
+
+		for _, user := range mentioned {
+			// Al autor del chirp respondido ya le llega la respuesta
+			if user.DeletedAt.Valid || (replyToID.Valid && user.ID == parent.UserID) {
+				continue
+			}
+			n, ok, err := s.notify(ctx, q, notification{
+				userID:   user.ID,
+				actorID:  author.ID,
+				typ:      NotificationMention,
+				groupKey: NotificationMention,
+				chirpID:  uuid.NullUUID{UUID: chirp.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if ok {
+				notifications = append(notifications, n)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return database.Chirp{}, database.User{}, err
+	}
+
+	event := chirpEvent(events.ChirpCreated, chirp)
+	event.Chirp = &chirp
+	s.publish(ctx, event)
+	s.publishNotifications(ctx, notifications)
+	return chirp, author, nil
+}
+
+// DeleteChirp borra un chirp de userID. Solo el autor puede borrar sus chirps.
+// Si unchanged no es nil, se llama con el chirp actual dentro de la transacción y debe
+// confirmar que es la versión que vio el cliente; si no, se devuelve ErrChirpModified.
+func (s *Service) DeleteChirp(ctx context.Context, userID, chirpID uuid.UUID, unchanged func(database.Chirp) bool) error {
+	var chirp database.Chirp
+	err := s.inTx(ctx, func(q Queries) error {
+		var err error
+		chirp, err = q.GetChirp(ctx, chirpID)
+		if err != nil {
+			return notFound(err, ErrChirpNotFound)
+		}
+		if chirp.UserID != userID {
+			return ErrNotChirpOwner
+		}
+		if unchanged != nil && !unchanged(chirp) {
+			return ErrChirpModified
+		}
+		return notFound(q.DeleteChirp(ctx, chirp.ID), ErrChirpNotFound)
+	})
+	if err == nil {
+		s.publish(ctx, chirpEvent(events.ChirpDeleted, chirp))
+	}
+	return err
+}
+
+// chirpEvent crea un evento de tipo eventType sobre chirp, sin el chirp completo.
+func chirpEvent(eventType string, chirp database.Chirp) events.Event {
+	return events.Event{
+		Type:     eventType,
+		ChirpID:  chirp.ID,
+		AuthorID: chirp.UserID,
+		Hashtags: ExtractHashtags(chirp.Body),
+	}
+}
+
+// isBlockedBy indica si blockerID bloqueó a userID.
+func isBlockedBy(ctx context.Context, q Queries, blockerID, userID uuid.UUID) (bool, error) {
+	if blockerID == userID {
+		return false, nil
+	}
+	return q.IsBlocked(ctx, database.IsBlockedParams{
+		BlockerID: blockerID,
+		BlockedID: userID,
+	})
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9_]{3,30})\b`)
+
+// ExtractMentions devuelve los usernames mencionados con @, en minúsculas y sin duplicados.
+func ExtractMentions(body string) []string {
+	seen := map[string]struct{}{}
+	var mentions []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.ToLower(match[1])
+		if _, ok := seen[username]; ok {
+			continue
+		}
+		seen[username] = struct{}{}
+		mentions = append(mentions, username)
+	}
+	return mentions
+}
+
+var hashtagPattern = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_]{1,50})`)
+
+// ExtractHashtags devuelve los hashtags del cuerpo, sin # y en minúsculas y sin duplicados.
+func ExtractHashtags(body string) []string {
+	seen := map[string]struct{}{}
+	var hashtags []string
+	for _, match := range hashtagPattern.FindAllStringSubmatch(body, -1) {
+		tag := strings.ToLower(match[1])
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		hashtags = append(hashtags, tag)
+	}
+	return hashtags
+}
+
+var badWords = map[string]struct{}{
+	"kerfuffle": {},
+	"sharbert":  {},
+	"fornax":    {},
+}
+
+// CleanChirp valida la longitud del chirp y censura las palabras prohibidas.
+func CleanChirp(body string, maxLength int) (string, error) {
+	if len(body) > maxLength {
+		return "", ErrChirpTooLong
+	}
+
+	words := strings.Split(body, " ")
+	for i, word := range words {
+		if _, ok := badWords[strings.ToLower(word)]; ok {
+			words[i] = "****"
+		}
+	}
+	return strings.Join(words, " "), nil
+}
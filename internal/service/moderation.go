@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/google/uuid"
+)
+
+// Motivos aceptados al reportar un chirp o un usuario
+var reportReasons = map[string]struct{}{
+	"spam":           {},
+	"harassment":     {},
+	"hate_speech":    {},
+	"violence":       {},
+	"sexual_content": {},
+	"misinformation": {},
+	"impersonation":  {},
+	"other":          {},
+}
+
+// Estados de un reporte
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusResolved = "resolved"
+)
+
+// Acciones que un moderador puede tomar sobre un reporte
+const (
+	ReportActionDismiss       = "dismiss"
+	ReportActionHideChirp     = "hide_chirp"
+	ReportActionDeleteChirp   = "delete_chirp"
+	ReportActionSuspendAuthor = "suspend_author"
+)
+
+const (
+	defaultSuspendHours    = 24
+	maxReportDetailsLength = 1000
+)
+
+// recordAudit guarda una acción administrativa en la misma transacción que la acción:
+// si no se puede auditar, la acción tampoco se aplica.
+func recordAudit(ctx context.Context, q Queries, actorID uuid.UUID, action, targetType string, targetID uuid.UUID, details string) error {
+	_, err := q.CreateAuditLog(ctx, database.CreateAuditLogParams{
+		ActorID:    uuid.NullUUID{UUID: actorID, Valid: true},
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+	})
+	return err
+}
+
+// updateAndAudit aplica update al usuario targetID y registra action en la auditoría.
+func (s *Service) updateAndAudit(ctx context.Context, actorID uuid.UUID, action, details string, update func(q Queries) (database.User, error)) (database.User, error) {
+	var user database.User
+	err := s.inTx(ctx, func(q Queries) error {
+		var err error
+		user, err = update(q)
+		if err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+		return recordAudit(ctx, q, actorID, action, "user", user.ID, details)
+	})
+	return user, err
+}
+
+// SetUserRole cambia el rol de targetID. Solo un admin puede hacerlo y nunca sobre sí
+// mismo: así siempre queda al menos un admin, el que hace el cambio. Como el actor se
+// relee en la transacción, dos admins que se degradan a la vez no pueden dejar cero.
+func (s *Service) SetUserRole(ctx context.Context, actorID, targetID uuid.UUID, role string) (database.User, error) {
+	if !ValidRole(role) {
+		return database.User{}, ErrInvalidRole
+	}
+	if actorID == targetID {
+		return database.User{}, ErrCannotTargetSelf
+	}
+	return s.updateAndAudit(ctx, actorID, "user.role_changed", "role="+role, func(q Queries) (database.User, error) {
+		actor, err := q.GetUserByID(ctx, actorID)
+		if err != nil {
+			return database.User{}, err
+		}
+		if !HasRole(actor, RoleAdmin) {
+			return database.User{}, ErrCannotModerateUser
+		}
+		return q.SetUserRole(ctx, database.SetUserRoleParams{ID: targetID, Role: role})
+	})
+}
+
+// SuspendUser suspende a targetID durante hours horas. targetID debe tener un rol
+// inferior al de actorID.
+func (s *Service) SuspendUser(ctx context.Context, actorID, targetID uuid.UUID, hours int) (database.User, error) {
+	if hours <= 0 {
+		return database.User{}, ErrInvalidSuspension
+	}
+	return s.updateAndAudit(ctx, actorID, "user.suspended", fmt.Sprintf("hours=%d", hours), func(q Queries) (database.User, error) {
+		if err := checkOutranks(ctx, q, actorID, targetID); err != nil {
+			return database.User{}, err
+		}
+		return suspend(ctx, q, targetID, hours)
+	})
+}
+
+// BanUser banea a targetID de forma indefinida. targetID debe tener un rol inferior al
+// de actorID.
+func (s *Service) BanUser(ctx context.Context, actorID, targetID uuid.UUID) (database.User, error) {
+	if actorID == targetID {
+		return database.User{}, ErrCannotTargetSelf
+	}
+	return s.updateAndAudit(ctx, actorID, "user.banned", "", func(q Queries) (database.User, error) {
+		if err := checkOutranks(ctx, q, actorID, targetID); err != nil {
+			return database.User{}, err
+		}
+		return q.BanUser(ctx, targetID)
+	})
+}
+
+// ReinstateUser levanta la suspensión o el baneo de targetID, que debe tener un rol
+// inferior al de actorID.
+func (s *Service) ReinstateUser(ctx context.Context, actorID, targetID uuid.UUID) (database.User, error) {
+	if actorID == targetID {
+		return database.User{}, ErrCannotTargetSelf
+	}
+	return s.updateAndAudit(ctx, actorID, "user.reinstated", "", func(q Queries) (database.User, error) {
+		if err := checkOutranks(ctx, q, actorID, targetID); err != nil {
+			return database.User{}, err
+		}
+		return q.ReinstateUser(ctx, targetID)
+	})
+}
+
+// SetChirpyRed activa o desactiva Chirpy Red manualmente y lo guarda en el historial de suscripción.
+func (s *Service) SetChirpyRed(ctx context.Context, actorID, targetID uuid.UUID, enabled bool) (database.User, error) {
+	action, event := "user.chirpy_red_revoked", "revoked"
+	if enabled {
+		action, event = "user.chirpy_red_granted", "granted"
+	}
+	return s.updateAndAudit(ctx, actorID, action, "", func(q Queries) (database.User, error) {
+		user, err := q.SetChirpyRed(ctx, database.SetChirpyRedParams{ID: targetID, IsChirpyRed: enabled})
+		if err != nil {
+			return user, err
+		}
+		_, err = q.CreateSubscriptionEvent(ctx, database.CreateSubscriptionEventParams{
+			UserID: user.ID,
+			Event:  event,
+			Source: "admin",
+		})
+		return user, err
+	})
+}
+
+// RemoveChirp borra cualquier chirp, sin importar el autor.
+func (s *Service) RemoveChirp(ctx context.Context, actorID, chirpID uuid.UUID) error {
+	var chirp database.Chirp
+	err := s.inTx(ctx, func(q Queries) error {
+		var err error
+		chirp, err = q.GetChirp(ctx, chirpID)
+		if err != nil {
+			return notFound(err, ErrChirpNotFound)
+		}
+		if err := q.DeleteChirp(ctx, chirp.ID); err != nil {
+			return notFound(err, ErrChirpNotFound)
+		}
+		return recordAudit(ctx, q, actorID, "chirp.deleted", "chirp", chirp.ID, "author="+chirp.UserID.String())
+	})
+	if err == nil {
+		s.publish(ctx, chirpEvent(events.ChirpDeleted, chirp))
+	}
+	return err
+}
+
+// ValidateReport comprueba el motivo y la longitud de los detalles de un reporte.
+func ValidateReport(reason, details string) error {
+	if _, ok := reportReasons[reason]; !ok {
+		return ErrInvalidReportReason
+	}
+	if len(details) > maxReportDetailsLength {
+		return ErrReportDetailsTooLong
+	}
+	return nil
+}
+
+// ReportChirp registra el reporte de reporterID sobre un chirp ajeno.
+func (s *Service) ReportChirp(ctx context.Context, reporterID, chirpID uuid.UUID, reason, details string) (database.Report, error) {
+	if err := ValidateReport(reason, details); err != nil {
+		return database.Report{}, err
+	}
+
+	var report database.Report
+	err := s.inTx(ctx, func(q Queries) error {
+		chirp, err := q.GetChirp(ctx, chirpID)
+		if err != nil {
+			return notFound(err, ErrChirpNotFound)
+		}
+		if chirp.UserID == reporterID {
+			return ErrCannotTargetSelf
+		}
+
+		report, err = q.CreateReport(ctx, database.CreateReportParams{
+			ReporterID:     reporterID,
+			ReportedUserID: chirp.UserID,
+			ChirpID:        uuid.NullUUID{UUID: chirp.ID, Valid: true},
+			Reason:         reason,
+			Details:        details,
+		})
+		return err
+	})
+	return report, err
+}
+
+// ReportUser registra el reporte de reporterID sobre otro usuario.
+func (s *Service) ReportUser(ctx context.Context, reporterID, targetID uuid.UUID, reason, details string) (database.Report, error) {
+	if err := ValidateReport(reason, details); err != nil {
+		return database.Report{}, err
+	}
+	if targetID == reporterID {
+		return database.Report{}, ErrCannotTargetSelf
+	}
+
+	var report database.Report
+	err := s.inTx(ctx, func(q Queries) error {
+		if _, err := q.GetUserByID(ctx, targetID); err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+
+		var err error
+		report, err = q.CreateReport(ctx, database.CreateReportParams{
+			ReporterID:     reporterID,
+			ReportedUserID: targetID,
+			Reason:         reason,
+			Details:        details,
+		})
+		return err
+	})
+	return report, err
+}
+
+// ListReports devuelve una página de los reportes con el estado indicado, por defecto
+// los abiertos.
+func (s *Service) ListReports(ctx context.Context, status string, limit, offset int32) ([]database.Report, error) {
+	if status == "" {
+		status = ReportStatusOpen
+	}
+	if status != ReportStatusOpen && status != ReportStatusResolved {
+		return nil, ErrInvalidReportStatus
+	}
+	return s.store.ListReports(ctx, database.ListReportsParams{
+		Status: status,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// ResolveReport aplica action sobre un reporte abierto y lo marca como resuelto.
+// suspendHours solo se usa con ReportActionSuspendAuthor; si es 0 se suspende un día.
+func (s *Service) ResolveReport(ctx context.Context, moderatorID, reportID uuid.UUID, action string, suspendHours int) (database.Report, error) {
+	removesChirp := action == ReportActionHideChirp || action == ReportActionDeleteChirp
+	var resolved database.Report
+	var chirp database.Chirp
+	err := s.inTx(ctx, func(q Queries) error {
+		report, err := q.GetReport(ctx, reportID)
+		if err != nil {
+			return notFound(err, ErrReportNotFound)
+		}
+		if report.Status != ReportStatusOpen {
+			return ErrReportAlreadyResolved
+		}
+
+		// El chirp se lee antes de ocultarlo o borrarlo para anunciar su retirada
+		if removesChirp && report.ChirpID.Valid {
+			if chirp, err = q.GetChirp(ctx, report.ChirpID.UUID); err != nil {
+				return notFound(err, ErrChirpNotFound)
+			}
+		}
+
+		resolution, err := applyReportAction(ctx, q, moderatorID, report, action, suspendHours)
+		if err != nil {
+			return err
+		}
+
+		resolved, err = q.ResolveReport(ctx, database.ResolveReportParams{
+			ID:         report.ID,
+			Resolution: sql.NullString{String: resolution, Valid: true},
+			ResolvedBy: uuid.NullUUID{UUID: moderatorID, Valid: true},
+		})
+		if database.IsNotFound(err) {
+			return ErrReportAlreadyResolved
+		}
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, moderatorID, "report.resolved", "report", resolved.ID, "resolution="+resolution)
+	})
+	if err == nil && removesChirp {
+		s.publish(ctx, chirpEvent(events.ChirpDeleted, chirp))
+	}
+	return resolved, err
+}
+
+// applyReportAction ejecuta la acción de moderación y devuelve la resolución del reporte.
+func applyReportAction(ctx context.Context, q Queries, moderatorID uuid.UUID, report database.Report, action string, suspendHours int) (string, error) {
+	reportRef := "report=" + report.ID.String()
+
+	switch action {
+	case ReportActionDismiss:
+		return "dismissed", nil
+
+	case ReportActionHideChirp:
+		if !report.ChirpID.Valid {
+			return "", ErrReportHasNoChirp
+		}
+		if _, err := q.HideChirp(ctx, report.ChirpID.UUID); err != nil {
+			return "", notFound(err, ErrChirpNotFound)
+		}
+		return "chirp_hidden", recordAudit(ctx, q, moderatorID, "chirp.hidden", "chirp", report.ChirpID.UUID, reportRef)
+
+	case ReportActionDeleteChirp:
+		if !report.ChirpID.Valid {
+			return "", ErrReportHasNoChirp
+		}
+		if err := q.DeleteChirp(ctx, report.ChirpID.UUID); err != nil {
+			return "", notFound(err, ErrChirpNotFound)
+		}
+		return "chirp_deleted", recordAudit(ctx, q, moderatorID, "chirp.deleted", "chirp", report.ChirpID.UUID, reportRef)
+
+	case ReportActionSuspendAuthor:
+		hours := suspendHours
+		if hours <= 0 {
+			hours = defaultSuspendHours
+		}
+		if err := checkOutranks(ctx, q, moderatorID, report.ReportedUserID); err != nil {
+			return "", err
+		}
+		if _, err := suspend(ctx, q, report.ReportedUserID, hours); err != nil {
+			return "", notFound(err, ErrUserNotFound)
+		}
+		details := fmt.Sprintf("hours=%d %s", hours, reportRef)
+		return "author_suspended", recordAudit(ctx, q, moderatorID, "user.suspended", "user", report.ReportedUserID, details)
+	}
+
+	return "", ErrInvalidModerationAction
+}
+
+// checkOutranks devuelve ErrCannotModerateUser si el rol de targetID no es estrictamente
+// inferior al de actorID: un moderador no puede sancionar a otro moderador ni a un admin.
+func checkOutranks(ctx context.Context, q Queries, actorID, targetID uuid.UUID) error {
+	actor, err := q.GetUserByID(ctx, actorID)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	target, err := q.GetUserByID(ctx, targetID)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	if roleRank[target.Role] >= roleRank[actor.Role] {
+		return ErrCannotModerateUser
+	}
+	return nil
+}
+
+func suspend(ctx context.Context, q Queries, userID uuid.UUID, hours int) (database.User, error) {
+	until := time.Now().UTC().Add(time.Duration(hours) * time.Hour)
+	return q.SuspendUser(ctx, database.SuspendUserParams{
+		ID:             userID,
+		SuspendedUntil: sql.NullTime{Time: until, Valid: true},
+	})
+}
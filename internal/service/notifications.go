@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/google/uuid"
+)
+
+// Tipos de notificación. Cada tipo se puede desactivar en las preferencias del usuario.
+// Las respuestas y los likes se agrupan por el chirp que los recibe; las menciones y
+// los follows, por usuario.
+const (
+	NotificationMention = "mention"
+	NotificationReply   = "reply"
+	NotificationLike    = "like"
+	NotificationFollow  = "follow"
+)
+
+// NotificationTypes son los tipos que existen, en el orden en que se muestran.
+var NotificationTypes = []string{NotificationMention, NotificationReply, NotificationLike, NotificationFollow}
+
+// ValidNotificationType indica si typ es un tipo de notificación.
+func ValidNotificationType(typ string) bool {
+	for _, t := range NotificationTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// notification es algo que le pasó a userID por culpa de actorID. Las notificaciones
+// sin leer con el mismo typ y groupKey se agrupan en una sola ("Ana y 4 más te
+// mencionaron") mientras la última sea de hace menos de la ventana configurada.
+type notification struct {
+	userID   uuid.UUID
+	actorID  uuid.UUID
+	typ      string
+	groupKey string
+	chirpID  uuid.NullUUID
+}
+
+// notify guarda n en la misma transacción que la acción que la provoca. Devuelve la
+// notificación creada o actualizada, o false si no hay que avisar: el actor es el
+// propio usuario, está silenciado o el usuario desactivó ese tipo.
+func (s *Service) notify(ctx context.Context, q Queries, n notification) (database.Notification, bool, error) {
+	if n.userID == n.actorID {
+		return database.Notification{}, false, nil
+	}
+	enabled, err := notificationEnabled(ctx, q, n.userID, n.typ)
+	if err != nil || !enabled {
+		return database.Notification{}, false, err
+	}
+	mutes, err := q.ListMutes(ctx, n.userID)
+	if err != nil {
+		return database.Notification{}, false, err
+	}
+	for _, mute := range mutes {
+		if mute.MutedID == n.actorID {
+			return database.Notification{}, false, nil
+		}
+	}
+
+	actorID := uuid.NullUUID{UUID: n.actorID, Valid: true}
+	if s.notificationCollapseWindow > 0 {
+		group, err := q.GetUnreadNotificationGroup(ctx, database.GetUnreadNotificationGroupParams{
+			UserID:   n.userID,
+			Type:     n.typ,
+			GroupKey: n.groupKey,
+			Since:    time.Now().UTC().Add(-s.notificationCollapseWindow),
+		})
+		if err == nil {
+			// Un actor que ya estaba en el grupo no suma, pero el grupo sube igual
+			added, err := q.AddNotificationActor(ctx, database.AddNotificationActorParams{
+				NotificationID: group.ID,
+				ActorID:        n.actorID,
+			})
+			if err != nil {
+				return database.Notification{}, false, err
+			}
+			group, err = q.BumpNotification(ctx, database.BumpNotificationParams{
+				ID:        group.ID,
+				ChirpID:   n.chirpID,
+				ActorID:   actorID,
+				NewActors: int32(added),
+			})
+			return group, err == nil, err
+		}
+		if !database.IsNotFound(err) {
+			return database.Notification{}, false, err
+		}
+	}
+
+	created, err := q.CreateNotification(ctx, database.CreateNotificationParams{
+		UserID:   n.userID,
+		Type:     n.typ,
+		GroupKey: n.groupKey,
+		ChirpID:  n.chirpID,
+		ActorID:  actorID,
+	})
+	if err != nil {
+		return database.Notification{}, false, err
+	}
+	_, err = q.AddNotificationActor(ctx, database.AddNotificationActorParams{
+		NotificationID: created.ID,
+		ActorID:        n.actorID,
+	})
+	return created, err == nil, err
+}
+
+// publishNotifications avisa en tiempo real de las notificaciones ya confirmadas.
+func (s *Service) publishNotifications(ctx context.Context, notifications []database.Notification) {
+	for i := range notifications {
+		s.publish(ctx, events.Event{
+			Type:         events.NotificationCreated,
+			ChirpID:      notifications[i].ChirpID.UUID,
+			RecipientID:  notifications[i].UserID,
+			Notification: &notifications[i],
+		})
+	}
+}
+
+// notificationEnabled indica si userID quiere notificaciones de tipo typ. Sin
+// preferencia guardada, todos los tipos están activados.
+func notificationEnabled(ctx context.Context, q Queries, userID uuid.UUID, typ string) (bool, error) {
+	prefs, err := notificationPreferences(ctx, q, userID)
+	if err != nil {
+		return false, err
+	}
+	return prefs[typ], nil
+}
+
+func notificationPreferences(ctx context.Context, q Queries, userID uuid.UUID) (map[string]bool, error) {
+	rows, err := q.ListNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(map[string]bool, len(NotificationTypes))
+	for _, typ := range NotificationTypes {
+		prefs[typ] = true
+	}
+	for _, row := range rows {
+		if _, ok := prefs[row.Type]; ok {
+			prefs[row.Type] = row.Enabled
+		}
+	}
+	return prefs, nil
+}
+
+// NotificationPreferences devuelve qué tipos de notificación tiene activados userID.
+func (s *Service) NotificationPreferences(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	return notificationPreferences(ctx, s.store, userID)
+}
+
+// SetNotificationPreferences activa o desactiva los tipos de changes para userID y
+// devuelve las preferencias resultantes. Los tipos que no aparecen no cambian.
+func (s *Service) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, changes map[string]bool) (map[string]bool, error) {
+	for typ := range changes {
+		if !ValidNotificationType(typ) {
+			return nil, ErrInvalidNotificationType
+		}
+	}
+
+	var prefs map[string]bool
+	err := s.inTx(ctx, func(q Queries) error {
+		for typ, enabled := range changes {
+			_, err := q.SetNotificationPreference(ctx, database.SetNotificationPreferenceParams{
+				UserID:  userID,
+				Type:    typ,
+				Enabled: enabled,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		prefs, err = notificationPreferences(ctx, q, userID)
+		return err
+	})
+	return prefs, err
+}
+
+// NotificationPage es una página de notificaciones y el total de no leídas del usuario.
+type NotificationPage struct {
+	Notifications []database.ListNotificationsRow
+	UnreadCount   int64
+}
+
+// ListNotifications devuelve las notificaciones de userID, de la más reciente a la más
+// antigua, y cuántas tiene sin leer. Con unreadOnly solo devuelve las no leídas.
+func (s *Service) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) (NotificationPage, error) {
+	var page NotificationPage
+	// La página y el contador salen de la misma transacción para que cuadren
+	err := s.inTx(ctx, func(q Queries) error {
+		var err error
+		page.Notifications, err = q.ListNotifications(ctx, database.ListNotificationsParams{
+			UserID:     userID,
+			UnreadOnly: unreadOnly,
+			RowLimit:   limit,
+			RowOffset:  offset,
+		})
+		if err != nil {
+			return err
+		}
+		page.UnreadCount, err = q.CountUnreadNotifications(ctx, userID)
+		return err
+	})
+	return page, err
+}
+
+// MarkNotificationRead marca como leída una notificación de userID. Las de otros
+// usuarios no se distinguen de las que no existen.
+func (s *Service) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	_, err := s.store.MarkNotificationRead(ctx, database.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	return notFound(err, ErrNotificationNotFound)
+}
+
+// MarkAllNotificationsRead marca como leídas todas las notificaciones de userID.
+func (s *Service) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) error {
+	_, err := s.store.MarkAllNotificationsRead(ctx, userID)
+	return err
+}
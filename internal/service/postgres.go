@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+)
+
+// PostgresStore implementa Store sobre Postgres. Las transacciones son SERIALIZABLE:
+// si dos chocan, Postgres aborta una con serialization_failure y el servicio la reintenta.
+type PostgresStore struct {
+	*database.Queries
+	db *sql.DB
+}
+
+// NewPostgresStore crea un PostgresStore sobre db.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{Queries: database.New(db), db: db}
+}
+
+func (s *PostgresStore) InTx(ctx context.Context, fn func(q Queries) error) error {
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	return RunTx(ctx, s.db, opts, func(tx *sql.Tx) error {
+		return fn(s.Queries.WithTx(tx))
+	})
+}
@@ -0,0 +1,140 @@
+// Package service contiene las operaciones de Chirpy que combinan varias consultas.
+// Cada operación corre en una única transacción, de modo que las comprobaciones
+// (existencia, dueño, estado de la cuenta) y las escrituras que dependen de ellas
+// no se intercalan con otras peticiones. Las reglas de dominio viven aquí y los
+// handlers solo traducen HTTP a llamadas al servicio y errores a respuestas.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+)
+
+// Errores de dominio. Los handlers los traducen a códigos de la API; los errores de
+// base de datos que no tienen traducción propia se devuelven tal cual.
+var (
+	ErrInvalidCredentials       = errors.New("incorrect email or password")
+	ErrIncorrectPassword        = errors.New("incorrect password")
+	ErrAccountPendingDeletion   = errors.New("account is pending deletion")
+	ErrAccountBanned            = errors.New("account is banned")
+	ErrAccountSuspended         = errors.New("account is suspended")
+	ErrAccountAlreadyDeleted    = errors.New("account is already pending deletion")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrEmailInUse               = errors.New("email is already in use")
+	ErrUsernameTaken            = errors.New("username is already taken")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrInvalidRole              = errors.New("invalid role")
+	ErrInvalidSuspension        = errors.New("suspension must be at least one hour")
+
+	ErrChirpNotFound    = errors.New("chirp not found")
+	ErrChirpTooLong     = errors.New("chirp is too long")
+	ErrNotChirpOwner    = errors.New("chirp belongs to another user")
+	ErrChirpModified    = errors.New("chirp changed since the client fetched it")
+	ErrMentionBlocked   = errors.New("mentioned user has blocked the author")
+	ErrCannotTargetSelf = errors.New("users cannot target themselves")
+	ErrBlockedByUser    = errors.New("user has blocked the actor")
+
+	ErrReportNotFound          = errors.New("report not found")
+	ErrInvalidReportStatus     = errors.New("invalid report status")
+	ErrReportAlreadyResolved   = errors.New("report is already resolved")
+	ErrReportHasNoChirp        = errors.New("report is not about a chirp")
+	ErrInvalidReportReason     = errors.New("invalid report reason")
+	ErrReportDetailsTooLong    = errors.New("report details are too long")
+	ErrInvalidModerationAction = errors.New("invalid moderation action")
+	ErrCannotModerateUser      = errors.New("target's role is not below the actor's")
+
+	ErrInvalidNotificationType = errors.New("invalid notification type")
+	ErrNotificationNotFound    = errors.New("notification not found")
+)
+
+// maxTxAttempts es cuántas veces se ejecuta una transacción que Postgres aborta por
+// conflictos de serialización antes de devolver el error.
+const maxTxAttempts = 3
+
+// Publisher recibe los eventos de los chirps después del commit de cada operación.
+type Publisher interface {
+	Publish(ctx context.Context, e events.Event) error
+}
+
+// Service ejecuta las operaciones de dominio sobre un Store.
+type Service struct {
+	store  Store
+	events Publisher
+
+	jwtSecret           string
+	accessTokenTTL      time.Duration
+	refreshTokenTTL     time.Duration
+	maxChirpLength      int
+	deletionGracePeriod time.Duration
+
+	emailVerificationTTL time.Duration
+
+	notificationCollapseWindow time.Duration
+}
+
+// New crea un Service sobre store con los límites y la configuración de auth de cfg.
+func New(store Store, cfg config.Config) *Service {
+	return &Service{
+		store:               store,
+		jwtSecret:           cfg.Auth.JWTSecret,
+		accessTokenTTL:      cfg.Auth.AccessTokenTTL,
+		refreshTokenTTL:     cfg.Auth.RefreshTokenTTL,
+		maxChirpLength:      cfg.Chirps.MaxLength,
+		deletionGracePeriod: cfg.Accounts.DeletionGracePeriod,
+
+		emailVerificationTTL: cfg.Auth.EmailVerificationTTL,
+
+		notificationCollapseWindow: cfg.Notifications.CollapseWindow,
+	}
+}
+
+// SetPublisher hace que el servicio publique en p los cambios de los chirps.
+func (s *Service) SetPublisher(p Publisher) {
+	s.events = p
+}
+
+// publish envía e si hay un Publisher. La operación ya se confirmó, así que un fallo
+// solo se registra: los clientes en tiempo real se pierden el evento.
+func (s *Service) publish(ctx context.Context, e events.Event) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Publish(context.WithoutCancel(ctx), e); err != nil {
+		slog.ErrorContext(ctx, "could not publish event", "type", e.Type, "chirp_id", e.ChirpID, "error", err)
+	}
+}
+
+// inTx ejecuta fn en una transacción y la repite desde el principio si la base de datos
+// la abortó por un conflicto con otra transacción. fn no debe tener efectos fuera de q.
+func (s *Service) inTx(ctx context.Context, fn func(q Queries) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = s.store.InTx(ctx, fn)
+		if !database.IsSerializationFailure(err) {
+			return err
+		}
+
+		// Espera creciente para no volver a chocar con la misma transacción
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 10 * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("transaction failed after %d attempts: %w", maxTxAttempts, err)
+}
+
+// notFound traduce "no existe la fila" al error de dominio domainErr, conservando el original.
+func notFound(err, domainErr error) error {
+	if database.IsNotFound(err) {
+		return fmt.Errorf("%w: %w", domainErr, err)
+	}
+	return err
+}
@@ -0,0 +1,540 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/auth"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/memstore"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func newService(store service.Store) *service.Service {
+	cfg := config.Default()
+	cfg.Auth.JWTSecret = "test-secret"
+	return service.New(store, cfg)
+}
+
+// createUser guarda un usuario con contraseña "hunter2".
+func createUser(t *testing.T, store service.Store, email string) database.User {
+	t.Helper()
+
+	hash, err := auth.HashPassword("hunter2")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	user, err := store.CreateUser(context.Background(), database.CreateUserParams{Email: email, HashedPassword: hash})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func TestDeleteChirpOwnership(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	jesse := createUser(t, store, "jesse@breakingbad.com")
+	chirp, _, err := svc.CreateChirp(ctx, walt.ID, "I am the one who knocks", uuid.NullUUID{})
+	if err != nil {
+		t.Fatalf("CreateChirp() error = %v", err)
+	}
+
+	if err := svc.DeleteChirp(ctx, jesse.ID, chirp.ID, nil); !errors.Is(err, service.ErrNotChirpOwner) {
+		t.Fatalf("DeleteChirp() by another user error = %v, want %v", err, service.ErrNotChirpOwner)
+	}
+	stale := func(database.Chirp) bool { return false }
+	if err := svc.DeleteChirp(ctx, walt.ID, chirp.ID, stale); !errors.Is(err, service.ErrChirpModified) {
+		t.Fatalf("DeleteChirp() of a modified chirp error = %v, want %v", err, service.ErrChirpModified)
+	}
+	if err := svc.DeleteChirp(ctx, walt.ID, chirp.ID, nil); err != nil {
+		t.Fatalf("DeleteChirp() by owner error = %v", err)
+	}
+	if err := svc.DeleteChirp(ctx, walt.ID, chirp.ID, nil); !errors.Is(err, service.ErrChirpNotFound) {
+		t.Fatalf("DeleteChirp() twice error = %v, want %v", err, service.ErrChirpNotFound)
+	}
+}
+
+func TestCreateChirpRules(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	hank := createUser(t, store, "hank@dea.gov")
+	hank, _ = store.PatchUser(ctx, database.PatchUserParams{ID: hank.ID, Username: sql.NullString{String: "hank", Valid: true}})
+	if err := store.BlockUser(ctx, database.BlockUserParams{BlockerID: hank.ID, BlockedID: walt.ID}); err != nil {
+		t.Fatalf("block: %v", err)
+	}
+
+	chirp, _, err := svc.CreateChirp(ctx, walt.ID, "what a kerfuffle", uuid.NullUUID{})
+	if err != nil || chirp.Body != "what a ****" {
+		t.Fatalf("CreateChirp() = %q, %v, want censored body", chirp.Body, err)
+	}
+
+	tests := []struct {
+		name   string
+		author uuid.UUID
+		body   string
+		want   error
+	}{
+		{"Too long", walt.ID, string(make([]byte, 141)), service.ErrChirpTooLong},
+		{"Mentions a user who blocked the author", walt.ID, "hi @hank", service.ErrMentionBlocked},
+		{"Unknown author", uuid.New(), "hello", service.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.CreateChirp(ctx, tt.author, tt.body, uuid.NullUUID{}); !errors.Is(err, tt.want) {
+				t.Errorf("CreateChirp() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// recordingPublisher guarda los eventos publicados.
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e events.Event) error {
+	p.events = append(p.events, e)
+	return nil
+}
+
+func TestPublishesChirpEvents(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+	published := &recordingPublisher{}
+	svc.SetPublisher(published)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	hank := createUser(t, store, "hank@dea.gov")
+	chirp, _, err := svc.CreateChirp(ctx, walt.ID, "#Chemistry is the study of #change #chemistry", uuid.NullUUID{})
+	if err != nil {
+		t.Fatalf("CreateChirp() error = %v", err)
+	}
+	report, err := svc.ReportChirp(ctx, hank.ID, chirp.ID, "spam", "")
+	if err != nil {
+		t.Fatalf("ReportChirp() error = %v", err)
+	}
+	if _, err := svc.ResolveReport(ctx, hank.ID, report.ID, service.ReportActionDeleteChirp, 0); err != nil {
+		t.Fatalf("ResolveReport() error = %v", err)
+	}
+
+	want := []string{events.ChirpCreated, events.ChirpDeleted}
+	if len(published.events) != len(want) {
+		t.Fatalf("published %d events, want %d", len(published.events), len(want))
+	}
+	for i, e := range published.events {
+		if e.Type != want[i] || e.ChirpID != chirp.ID || e.AuthorID != walt.ID {
+			t.Errorf("event %d = %s %s by %s, want %s %s by %s", i, e.Type, e.ChirpID, e.AuthorID, want[i], chirp.ID, walt.ID)
+		}
+		if got := strings.Join(e.Hashtags, ","); got != "chemistry,change" {
+			t.Errorf("event %d hashtags = %q, want %q", i, got, "chemistry,change")
+		}
+	}
+}
+
+func TestMentionNotifications(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+	published := &recordingPublisher{}
+	svc.SetPublisher(published)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	walt, _ = store.PatchUser(ctx, database.PatchUserParams{ID: walt.ID, Username: sql.NullString{String: "heisenberg", Valid: true}})
+	jesse := createUser(t, store, "jesse@breakingbad.com")
+	skyler := createUser(t, store, "skyler@breakingbad.com")
+	hank := createUser(t, store, "hank@dea.gov")
+	mention := func(author uuid.UUID) {
+		t.Helper()
+		if _, _, err := svc.CreateChirp(ctx, author, "@heisenberg say my name", uuid.NullUUID{}); err != nil {
+			t.Fatalf("CreateChirp() error = %v", err)
+		}
+	}
+	list := func() []database.ListNotificationsRow {
+		t.Helper()
+		rows, err := store.ListNotifications(ctx, database.ListNotificationsParams{UserID: walt.ID, UnreadOnly: true, RowLimit: 10})
+		if err != nil {
+			t.Fatalf("ListNotifications() error = %v", err)
+		}
+		return rows
+	}
+
+	// Las menciones sin leer se agrupan y cada actor cuenta una vez
+	mention(jesse.ID)
+	mention(jesse.ID)
+	mention(skyler.ID)
+	mention(walt.ID)
+	rows := list()
+	if len(rows) != 1 {
+		t.Fatalf("got %d unread notifications, want 1 collapsed group", len(rows))
+	}
+	if n := rows[0].Notification; n.Type != service.NotificationMention || n.ActorCount != 2 || n.ActorID.UUID != skyler.ID {
+		t.Errorf("notification = %s by %s (%d actors), want mention by %s (2 actors)", n.Type, n.ActorID.UUID, n.ActorCount, skyler.ID)
+	}
+
+	// Una vez leídas, la siguiente mención empieza otro grupo
+	if _, err := store.MarkAllNotificationsRead(ctx, walt.ID); err != nil {
+		t.Fatalf("MarkAllNotificationsRead() error = %v", err)
+	}
+	mention(hank.ID)
+	if rows := list(); len(rows) != 1 || rows[0].Notification.ActorCount != 1 {
+		t.Fatalf("after reading, got %+v, want a new notification with 1 actor", rows)
+	}
+
+	// Ni los silenciados ni los tipos desactivados notifican
+	if err := store.MuteUser(ctx, database.MuteUserParams{MuterID: walt.ID, MutedID: jesse.ID}); err != nil {
+		t.Fatalf("mute: %v", err)
+	}
+	if _, err := svc.SetNotificationPreferences(ctx, walt.ID, map[string]bool{"likes": false}); !errors.Is(err, service.ErrInvalidNotificationType) {
+		t.Fatalf("SetNotificationPreferences() with unknown type error = %v, want %v", err, service.ErrInvalidNotificationType)
+	}
+	if _, err := store.MarkAllNotificationsRead(ctx, walt.ID); err != nil {
+		t.Fatalf("MarkAllNotificationsRead() error = %v", err)
+	}
+	mention(jesse.ID)
+	prefs, err := svc.SetNotificationPreferences(ctx, walt.ID, map[string]bool{service.NotificationMention: false})
+	if err != nil || prefs[service.NotificationMention] {
+		t.Fatalf("SetNotificationPreferences() = %v, %v, want mentions disabled", prefs, err)
+	}
+	mention(skyler.ID)
+	if rows := list(); len(rows) != 0 {
+		t.Fatalf("got %d unread notifications, want none", len(rows))
+	}
+
+	var notified int
+	for _, e := range published.events {
+		if e.Type == events.NotificationCreated {
+			notified++
+			if e.RecipientID != walt.ID || e.Notification == nil {
+				t.Errorf("event = %+v, want a notification for %s", e, walt.ID)
+			}
+		}
+	}
+	if notified != 4 {
+		t.Errorf("published %d notification events, want 4", notified)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+
+	if _, err := svc.Login(ctx, "walt@breakingbad.com", "wrong"); !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Fatalf("Login() with wrong password error = %v, want %v", err, service.ErrInvalidCredentials)
+	}
+
+	// Iniciar sesión durante el periodo de gracia cancela la eliminación
+	if _, err := svc.DeleteAccount(ctx, walt.ID, "hunter2"); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	session, err := svc.Login(ctx, "walt@breakingbad.com", "hunter2")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if session.User.DeletedAt.Valid || session.AccessToken == "" {
+		t.Errorf("Login() = %+v, want restored account and access token", session)
+	}
+	user, err := store.GetUserFromRefreshToken(ctx, session.RefreshToken)
+	if err != nil || user.ID != walt.ID {
+		t.Errorf("refresh token belongs to %v (%v), want %v", user.ID, err, walt.ID)
+	}
+
+	// Una cuenta baneada no abre sesión ni guarda refresh tokens
+	if _, err := store.BanUser(ctx, walt.ID); err != nil {
+		t.Fatalf("ban: %v", err)
+	}
+	if _, err := svc.Login(ctx, "walt@breakingbad.com", "hunter2"); !errors.Is(err, service.ErrAccountBanned) {
+		t.Errorf("Login() banned error = %v, want %v", err, service.ErrAccountBanned)
+	}
+	sessions, _ := store.ListRefreshTokensForUser(ctx, walt.ID)
+	if len(sessions) != 1 {
+		t.Errorf("refresh tokens = %d, want 1", len(sessions))
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	createUser(t, store, "jesse@breakingbad.com")
+	email, password, bio := "Heisenberg@BreakingBad.com", "blue-sky", "Chemistry teacher"
+
+	_, _, err := svc.UpdateUser(ctx, walt.ID, service.UserChanges{Password: &password, CurrentPassword: "wrong"})
+	if !errors.Is(err, service.ErrIncorrectPassword) {
+		t.Errorf("UpdateUser() with wrong password error = %v, want %v", err, service.ErrIncorrectPassword)
+	}
+	taken := "JESSE@breakingbad.com"
+	_, _, err = svc.UpdateUser(ctx, walt.ID, service.UserChanges{Email: &taken, Bio: &bio})
+	if !errors.Is(err, service.ErrEmailInUse) {
+		t.Errorf("UpdateUser() with taken email error = %v, want %v", err, service.ErrEmailInUse)
+	}
+	if user, _ := store.GetUserByID(ctx, walt.ID); user.Bio != "" {
+		t.Errorf("bio = %q after failed update, want unchanged", user.Bio)
+	}
+
+	// El email queda pendiente hasta que se canjea el token de verificación
+	user, verification, err := svc.UpdateUser(ctx, walt.ID, service.UserChanges{
+		Email:           &email,
+		Password:        &password,
+		CurrentPassword: "hunter2",
+		Bio:             &bio,
+	})
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if user.Email != walt.Email || user.Bio != bio || verification.Email != "heisenberg@breakingbad.com" {
+		t.Fatalf("UpdateUser() = %+v, %+v, want pending lowercased email and new bio", user, verification)
+	}
+	verified, err := svc.VerifyEmail(ctx, verification.Token)
+	if err != nil || verified.Email != verification.Email {
+		t.Errorf("VerifyEmail() = %v, %v, want email %s", verified.Email, err, verification.Email)
+	}
+	if _, err := svc.Login(ctx, verification.Email, password); err != nil {
+		t.Errorf("Login() with new email and password error = %v", err)
+	}
+}
+
+func TestResolveReportRollsBack(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	hank := createUser(t, store, "hank@dea.gov")
+	store.SetUserRole(ctx, database.SetUserRoleParams{ID: hank.ID, Role: service.RoleModerator})
+	report, err := svc.ReportUser(ctx, hank.ID, walt.ID, "other", "")
+	if err != nil {
+		t.Fatalf("ReportUser() error = %v", err)
+	}
+
+	// Un reporte sobre un usuario no tiene chirp que ocultar: nada debe cambiar
+	if _, err := svc.ResolveReport(ctx, hank.ID, report.ID, service.ReportActionHideChirp, 0); !errors.Is(err, service.ErrReportHasNoChirp) {
+		t.Fatalf("ResolveReport() error = %v, want %v", err, service.ErrReportHasNoChirp)
+	}
+	if got, _ := store.GetReport(ctx, report.ID); got.Status != "open" {
+		t.Fatalf("report status = %q, want open", got.Status)
+	}
+
+	resolved, err := svc.ResolveReport(ctx, hank.ID, report.ID, service.ReportActionSuspendAuthor, 0)
+	if err != nil || resolved.Resolution.String != "author_suspended" {
+		t.Fatalf("ResolveReport() = %+v, %v", resolved, err)
+	}
+	if _, err := svc.ResolveReport(ctx, hank.ID, report.ID, service.ReportActionDismiss, 0); !errors.Is(err, service.ErrReportAlreadyResolved) {
+		t.Errorf("ResolveReport() twice error = %v, want %v", err, service.ErrReportAlreadyResolved)
+	}
+	logs, _ := store.ListAuditLogs(ctx, database.ListAuditLogsParams{Limit: 10})
+	if len(logs) != 2 {
+		t.Errorf("audit logs = %d, want 2 (suspension and resolution)", len(logs))
+	}
+
+	// La cola muestra por defecto los abiertos; el resuelto sale al pedirlo
+	if open, err := svc.ListReports(ctx, "", 10, 0); err != nil || len(open) != 0 {
+		t.Errorf("ListReports(open) = %d reports, %v, want none", len(open), err)
+	}
+	if done, err := svc.ListReports(ctx, service.ReportStatusResolved, 10, 0); err != nil || len(done) != 1 {
+		t.Errorf("ListReports(resolved) = %d reports, %v, want 1", len(done), err)
+	}
+	if _, err := svc.ListReports(ctx, "pending", 10, 0); !errors.Is(err, service.ErrInvalidReportStatus) {
+		t.Errorf("ListReports(pending) error = %v, want %v", err, service.ErrInvalidReportStatus)
+	}
+}
+
+// flakyStore simula que Postgres aborta las primeras transacciones por serialización.
+type flakyStore struct {
+	*memstore.Store
+	failures int
+	attempts int
+}
+
+func (s *flakyStore) InTx(ctx context.Context, fn func(q service.Queries) error) error {
+	s.attempts++
+	if s.attempts <= s.failures {
+		return &pq.Error{Code: "40001", Message: "could not serialize access"}
+	}
+	return s.Store.InTx(ctx, fn)
+}
+
+func TestSuspendRequiresLowerRole(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	hank := createUser(t, store, "hank@dea.gov")
+	gus := createUser(t, store, "gus@lospolloshermanos.com")
+	store.SetUserRole(ctx, database.SetUserRoleParams{ID: hank.ID, Role: service.RoleModerator})
+	store.SetUserRole(ctx, database.SetUserRoleParams{ID: gus.ID, Role: service.RoleAdmin})
+
+	tests := []struct {
+		name   string
+		actor  uuid.UUID
+		target uuid.UUID
+		want   error
+	}{
+		{"Moderator suspends user", hank.ID, walt.ID, nil},
+		{"Moderator suspends admin", hank.ID, gus.ID, service.ErrCannotModerateUser},
+		{"Admin suspends moderator", gus.ID, hank.ID, nil},
+		{"User suspends user", walt.ID, walt.ID, service.ErrCannotModerateUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SuspendUser(ctx, tt.actor, tt.target, 1)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("SuspendUser() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// La misma regla vale al resolver un reporte con suspend_author
+	report, err := svc.ReportUser(ctx, walt.ID, gus.ID, "other", "")
+	if err != nil {
+		t.Fatalf("ReportUser() error = %v", err)
+	}
+	if _, err := svc.ResolveReport(ctx, hank.ID, report.ID, service.ReportActionSuspendAuthor, 0); !errors.Is(err, service.ErrCannotModerateUser) {
+		t.Fatalf("ResolveReport() error = %v, want %v", err, service.ErrCannotModerateUser)
+	}
+	if got, _ := store.GetReport(ctx, report.ID); got.Status != "open" {
+		t.Errorf("report status = %q, want open", got.Status)
+	}
+}
+
+func TestBanAndRoleChangesRequireLowerRole(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	svc := newService(store)
+
+	walt := createUser(t, store, "walt@breakingbad.com")
+	hank := createUser(t, store, "hank@dea.gov")
+	gus := createUser(t, store, "gus@lospolloshermanos.com")
+	mike := createUser(t, store, "mike@lospolloshermanos.com")
+	store.SetUserRole(ctx, database.SetUserRoleParams{ID: hank.ID, Role: service.RoleModerator})
+	store.SetUserRole(ctx, database.SetUserRoleParams{ID: gus.ID, Role: service.RoleAdmin})
+	store.SetUserRole(ctx, database.SetUserRoleParams{ID: mike.ID, Role: service.RoleAdmin})
+
+	ban := func(actor, target uuid.UUID) error { _, err := svc.BanUser(ctx, actor, target); return err }
+	reinstate := func(actor, target uuid.UUID) error { _, err := svc.ReinstateUser(ctx, actor, target); return err }
+	demote := func(actor, target uuid.UUID) error {
+		_, err := svc.SetUserRole(ctx, actor, target, service.RoleUser)
+		return err
+	}
+
+	tests := []struct {
+		name   string
+		action func(actor, target uuid.UUID) error
+		actor  uuid.UUID
+		target uuid.UUID
+		want   error
+	}{
+		{"Admin bans user", ban, gus.ID, walt.ID, nil},
+		{"Admin bans moderator", ban, gus.ID, hank.ID, nil},
+		{"Admin bans admin", ban, gus.ID, mike.ID, service.ErrCannotModerateUser},
+		{"Admin bans itself", ban, gus.ID, gus.ID, service.ErrCannotTargetSelf},
+		{"Admin reinstates user", reinstate, gus.ID, walt.ID, nil},
+		{"Admin reinstates admin", reinstate, gus.ID, mike.ID, service.ErrCannotModerateUser},
+		{"Admin reinstates itself", reinstate, gus.ID, gus.ID, service.ErrCannotTargetSelf},
+		{"Admin demotes itself", demote, gus.ID, gus.ID, service.ErrCannotTargetSelf},
+		{"Moderator demotes admin", demote, hank.ID, gus.ID, service.ErrCannotModerateUser},
+		{"Admin demotes admin", demote, gus.ID, mike.ID, nil},
+		// Mike ya no es admin: no puede degradar al último que queda
+		{"Demoted admin demotes last admin", demote, mike.ID, gus.ID, service.ErrCannotModerateUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.action(tt.actor, tt.target); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if got, _ := store.GetUserByID(ctx, gus.ID); got.Role != service.RoleAdmin {
+		t.Errorf("last admin role = %q, want %q", got.Role, service.RoleAdmin)
+	}
+}
+
+func TestRetriesSerializationFailures(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		failures int
+		wantErr  bool
+	}{
+		{"Succeeds after a retry", 2, false},
+		{"Gives up after three attempts", 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &flakyStore{Store: memstore.New(), failures: tt.failures}
+			walt := createUser(t, store, "walt@breakingbad.com")
+
+			_, _, err := newService(store).CreateChirp(ctx, walt.ID, "Say my name", uuid.NullUUID{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateChirp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !database.IsSerializationFailure(err) {
+				t.Errorf("CreateChirp() error = %v, want the serialization failure", err)
+			}
+			if store.attempts != 3 {
+				t.Errorf("attempts = %d, want 3", store.attempts)
+			}
+		})
+	}
+}
+
+// windowStore guarda desde cuándo se buscan grupos de notificaciones para agrupar.
+type windowStore struct {
+	*memstore.Store
+	since []time.Time
+}
+
+func (s *windowStore) InTx(ctx context.Context, fn func(q service.Queries) error) error {
+	return s.Store.InTx(ctx, func(service.Queries) error { return fn(s) })
+}
+
+func (s *windowStore) GetUnreadNotificationGroup(ctx context.Context, arg database.GetUnreadNotificationGroupParams) (database.Notification, error) {
+	s.since = append(s.since, arg.Since)
+	return s.Store.GetUnreadNotificationGroup(ctx, arg)
+}
+
+func TestNotificationCollapseWindowIsUTC(t *testing.T) {
+	// Las columnas son TIMESTAMP sin zona: una hora local se compararía desplazada
+	local := time.Local
+	time.Local = time.FixedZone("CST", -6*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	ctx := context.Background()
+	store := &windowStore{Store: memstore.New()}
+	svc := newService(store)
+	walt := createUser(t, store, "walt@breakingbad.com")
+	jesse := createUser(t, store, "jesse@breakingbad.com")
+
+	if err := svc.FollowUser(ctx, jesse.ID, walt.ID); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if len(store.since) != 1 {
+		t.Fatalf("looked up %d notification groups, want 1", len(store.since))
+	}
+	if loc := store.since[0].Location(); loc != time.UTC {
+		t.Errorf("collapse window starts in %v, want UTC", loc)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// LikeChirp guarda que userID le dio like a chirpID y avisa al autor. Dar like dos
+// veces no es un error, pero solo el primero notifica. No se puede dar like a los
+// chirps de quien te bloqueó.
+func (s *Service) LikeChirp(ctx context.Context, userID, chirpID uuid.UUID) error {
+	var notifications []database.Notification
+	err := s.inTx(ctx, func(q Queries) error {
+		notifications = nil
+		if err := activeUser(ctx, q, userID); err != nil {
+			return err
+		}
+		chirp, err := publicChirp(ctx, q, chirpID)
+		if err != nil {
+			return err
+		}
+		if err := checkNotBlockedBy(ctx, q, chirp.UserID, userID); err != nil {
+			return err
+		}
+
+		added, err := q.LikeChirp(ctx, database.LikeChirpParams{
+			UserID:  userID,
+			ChirpID: chirp.ID,
+		})
+		if err != nil || added == 0 {
+			return err
+		}
+		n, ok, err := s.notify(ctx, q, notification{
+			userID:   chirp.UserID,
+			actorID:  userID,
+			typ:      NotificationLike,
+			groupKey: NotificationLike + ":" + chirp.ID.String(),
+			chirpID:  uuid.NullUUID{UUID: chirp.ID, Valid: true},
+		})
+		if ok {
+			notifications = append(notifications, n)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishNotifications(ctx, notifications)
+	return nil
+}
+
+// UnlikeChirp quita el like de userID a chirpID, si existía.
+func (s *Service) UnlikeChirp(ctx context.Context, userID, chirpID uuid.UUID) error {
+	return s.store.UnlikeChirp(ctx, database.UnlikeChirpParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+}
+
+// FollowUser hace que followerID siga a followedID y avisa a followedID. Seguir dos
+// veces no es un error, pero solo el primer follow notifica. No se puede seguir a
+// quien te bloqueó.
+func (s *Service) FollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	if followerID == followedID {
+		return ErrCannotTargetSelf
+	}
+
+	var notifications []database.Notification
+	err := s.inTx(ctx, func(q Queries) error {
+		notifications = nil
+		if err := activeUser(ctx, q, followerID); err != nil {
+			return err
+		}
+		followed, err := q.GetUserByID(ctx, followedID)
+		if err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+		if followed.DeletedAt.Valid {
+			return ErrUserNotFound
+		}
+		if err := checkNotBlockedBy(ctx, q, followed.ID, followerID); err != nil {
+			return err
+		}
+
+		added, err := q.FollowUser(ctx, database.FollowUserParams{
+			FollowerID: followerID,
+			FollowedID: followed.ID,
+		})
+		if err != nil || added == 0 {
+			return err
+		}
+		n, ok, err := s.notify(ctx, q, notification{
+			userID:   followed.ID,
+			actorID:  followerID,
+			typ:      NotificationFollow,
+			groupKey: NotificationFollow,
+		})
+		if ok {
+			notifications = append(notifications, n)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishNotifications(ctx, notifications)
+	return nil
+}
+
+// UnfollowUser hace que followerID deje de seguir a followedID, si lo seguía.
+func (s *Service) UnfollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	return s.store.UnfollowUser(ctx, database.UnfollowUserParams{
+		FollowerID: followerID,
+		FollowedID: followedID,
+	})
+}
+
+// ListFollowing devuelve a quién sigue userID, del follow más reciente al más antiguo.
+func (s *Service) ListFollowing(ctx context.Context, userID uuid.UUID) ([]database.Follow, error) {
+	return s.store.ListFollowing(ctx, userID)
+}
+
+// ListFollowers devuelve quién sigue a userID, del follow más reciente al más antiguo.
+func (s *Service) ListFollowers(ctx context.Context, userID uuid.UUID) ([]database.Follow, error) {
+	return s.store.ListFollowers(ctx, userID)
+}
+
+// BlockUser hace que blockerID bloquee a blockedID. Bloquear también deshace los
+// follows entre ambos, en los dos sentidos.
+func (s *Service) BlockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	if blockerID == blockedID {
+		return ErrCannotTargetSelf
+	}
+
+	return s.inTx(ctx, func(q Queries) error {
+		if _, err := q.GetUserByID(ctx, blockedID); err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+		err := q.BlockUser(ctx, database.BlockUserParams{
+			BlockerID: blockerID,
+			BlockedID: blockedID,
+		})
+		if err != nil {
+			return err
+		}
+		return q.DeleteFollowsBetween(ctx, database.DeleteFollowsBetweenParams{
+			UserA: blockerID,
+			UserB: blockedID,
+		})
+	})
+}
+
+// checkNotBlockedBy devuelve ErrBlockedByUser si blockerID bloqueó a userID.
+func checkNotBlockedBy(ctx context.Context, q Queries, blockerID, userID uuid.UUID) error {
+	blocked, err := isBlockedBy(ctx, q, blockerID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlockedByUser
+	}
+	return nil
+}
+
+// activeUser comprueba que userID existe y puede actuar.
+func activeUser(ctx context.Context, q Queries, userID uuid.UUID) error {
+	user, err := q.GetUserByID(ctx, userID)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	return CheckAccount(user)
+}
+
+// publicChirp devuelve chirpID si se puede responder o dar like: existe, no está
+// oculto por moderación y su autor no está pendiente de borrado.
+func publicChirp(ctx context.Context, q Queries, chirpID uuid.UUID) (database.Chirp, error) {
+	chirp, err := q.GetChirp(ctx, chirpID)
+	if err != nil {
+		return database.Chirp{}, notFound(err, ErrChirpNotFound)
+	}
+	if chirp.HiddenAt.Valid {
+		return database.Chirp{}, ErrChirpNotFound
+	}
+	author, err := q.GetUserByID(ctx, chirp.UserID)
+	if err != nil {
+		return database.Chirp{}, notFound(err, ErrChirpNotFound)
+	}
+	if author.DeletedAt.Valid {
+		return database.Chirp{}, ErrChirpNotFound
+	}
+	return chirp, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/google/uuid"
+)
+
+// Queries agrupa las consultas sobre las que trabajan los handlers y el servicio.
+// *database.Queries la implementa contra Postgres, sqlitedb.Store contra SQLite y
+// memstore.Store en memoria; cualquier implementación debe devolver errores que
+// database.ClassifyError sepa clasificar (sql.ErrNoRows, *pq.Error o ya envueltos).
+type Queries interface {
+	// Usuarios
+	CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error)
+	GetUserByEmail(ctx context.Context, email string) (database.User, error)
+	GetUserByUsername(ctx context.Context, username string) (database.User, error)
+	ListUsers(ctx context.Context, arg database.ListUsersParams) ([]database.User, error)
+	UpdateUser(ctx context.Context, arg database.UpdateUserParams) (database.User, error)
+	UpdateUserEmail(ctx context.Context, arg database.UpdateUserEmailParams) (database.User, error)
+	UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error)
+	PatchUser(ctx context.Context, arg database.PatchUserParams) (database.User, error)
+	UpgradeToChirpyRed(ctx context.Context, id uuid.UUID) (database.User, error)
+	SetChirpyRed(ctx context.Context, arg database.SetChirpyRedParams) (database.User, error)
+	SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (database.User, error)
+	SuspendUser(ctx context.Context, arg database.SuspendUserParams) (database.User, error)
+	BanUser(ctx context.Context, id uuid.UUID) (database.User, error)
+	ReinstateUser(ctx context.Context, id uuid.UUID) (database.User, error)
+	SoftDeleteUser(ctx context.Context, arg database.SoftDeleteUserParams) (database.User, error)
+	CancelUserDeletion(ctx context.Context, id uuid.UUID) (database.User, error)
+	Reset(ctx context.Context) error
+	PurgeDeletedUsers(ctx context.Context) (int64, error)
+
+	// Chirps
+	CreateChirp(ctx context.Context, arg database.CreateChirpParams) (database.Chirp, error)
+	GetChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error)
+	GetChirpWithAuthor(ctx context.Context, id uuid.UUID) (database.GetChirpWithAuthorRow, error)
+	GetChirps(ctx context.Context) ([]database.GetChirpsRow, error)
+	GetChirpsForViewer(ctx context.Context, viewerID uuid.UUID) ([]database.GetChirpsForViewerRow, error)
+	ListChirpsByUser(ctx context.Context, userID uuid.UUID) ([]database.Chirp, error)
+	HideChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error)
+	DeleteChirp(ctx context.Context, id uuid.UUID) error
+
+	// Refresh tokens y verificación de email
+	CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (database.RefreshToken, error)
+	GetUserFromRefreshToken(ctx context.Context, token string) (database.User, error)
+	ListRefreshTokensForUser(ctx context.Context, userID uuid.UUID) ([]database.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, token string) (database.RefreshToken, error)
+	RevokeAllRefreshTokensForUser(ctx context.Context, userID uuid.UUID) error
+	CreateEmailVerification(ctx context.Context, arg database.CreateEmailVerificationParams) (database.EmailVerification, error)
+	GetPendingEmailVerification(ctx context.Context, token string) (database.EmailVerification, error)
+	MarkEmailVerificationUsed(ctx context.Context, token string) error
+
+	// Bloqueos y silenciados
+	BlockUser(ctx context.Context, arg database.BlockUserParams) error
+	UnblockUser(ctx context.Context, arg database.UnblockUserParams) error
+	IsBlocked(ctx context.Context, arg database.IsBlockedParams) (bool, error)
+	ListBlocks(ctx context.Context, blockerID uuid.UUID) ([]database.Block, error)
+	ListBlockers(ctx context.Context, blockedID uuid.UUID) ([]database.Block, error)
+	MuteUser(ctx context.Context, arg database.MuteUserParams) error
+	UnmuteUser(ctx context.Context, arg database.UnmuteUserParams) error
+	ListMutes(ctx context.Context, muterID uuid.UUID) ([]database.Mute, error)
+
+	// Likes y follows
+	LikeChirp(ctx context.Context, arg database.LikeChirpParams) (int64, error)
+	UnlikeChirp(ctx context.Context, arg database.UnlikeChirpParams) error
+	ListLikesByUser(ctx context.Context, userID uuid.UUID) ([]database.Like, error)
+	FollowUser(ctx context.Context, arg database.FollowUserParams) (int64, error)
+	UnfollowUser(ctx context.Context, arg database.UnfollowUserParams) error
+	ListFollowing(ctx context.Context, followerID uuid.UUID) ([]database.Follow, error)
+	ListFollowers(ctx context.Context, followedID uuid.UUID) ([]database.Follow, error)
+	DeleteFollowsBetween(ctx context.Context, arg database.DeleteFollowsBetweenParams) error
+
+	// Moderación, auditoría y suscripciones
+	CreateReport(ctx context.Context, arg database.CreateReportParams) (database.Report, error)
+	GetReport(ctx context.Context, id uuid.UUID) (database.Report, error)
+	ListReports(ctx context.Context, arg database.ListReportsParams) ([]database.Report, error)
+	ResolveReport(ctx context.Context, arg database.ResolveReportParams) (database.Report, error)
+	CreateAuditLog(ctx context.Context, arg database.CreateAuditLogParams) (database.AdminAuditLog, error)
+	ListAuditLogs(ctx context.Context, arg database.ListAuditLogsParams) ([]database.AdminAuditLog, error)
+	CreateSubscriptionEvent(ctx context.Context, arg database.CreateSubscriptionEventParams) (database.SubscriptionEvent, error)
+	ListSubscriptionEvents(ctx context.Context, userID uuid.UUID) ([]database.SubscriptionEvent, error)
+
+	// Claves de idempotencia
+	CreateIdempotencyKey(ctx context.Context, arg database.CreateIdempotencyKeyParams) (int64, error)
+	GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg database.CompleteIdempotencyKeyParams) error
+	DeleteIdempotencyKey(ctx context.Context, arg database.DeleteIdempotencyKeyParams) error
+	PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+
+	// Notificaciones
+	GetUnreadNotificationGroup(ctx context.Context, arg database.GetUnreadNotificationGroupParams) (database.Notification, error)
+	CreateNotification(ctx context.Context, arg database.CreateNotificationParams) (database.Notification, error)
+	AddNotificationActor(ctx context.Context, arg database.AddNotificationActorParams) (int64, error)
+	BumpNotification(ctx context.Context, arg database.BumpNotificationParams) (database.Notification, error)
+	ListNotifications(ctx context.Context, arg database.ListNotificationsParams) ([]database.ListNotificationsRow, error)
+	CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg database.MarkNotificationReadParams) (database.Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	ListNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error)
+	SetNotificationPreference(ctx context.Context, arg database.SetNotificationPreferenceParams) (database.NotificationPreference, error)
+}
+
+// Store son las consultas más la posibilidad de agruparlas en una transacción.
+type Store interface {
+	Queries
+
+	// InTx ejecuta fn dentro de una transacción: si fn devuelve un error se hace
+	// rollback y, si no, commit. fn debe usar solo las consultas que recibe.
+	// Es un único intento; los reintentos los decide el servicio.
+	InTx(ctx context.Context, fn func(q Queries) error) error
+}
+
+var _ Queries = (*database.Queries)(nil)
+
+type freshReadsKey struct{}
+
+// FreshReads marca ctx para que GetUserByID y GetChirp lean de la base de datos aunque
+// el Store tenga un caché delante. Es para las comprobaciones de permisos: cada réplica
+// solo invalida su caché con sus propias escrituras, y un baneo o una degradación hecha
+// en otra réplica no puede esperar a que expire la entrada.
+func FreshReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadsKey{}, true)
+}
+
+// WantsFreshReads indica si ctx se marcó con FreshReads.
+func WantsFreshReads(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadsKey{}).(bool)
+	return fresh
+}
+
+// RunTx abre una transacción en db, ejecuta fn y hace commit o rollback según su resultado.
+func RunTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,66 @@
+package sqlitedb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Scheme es el prefijo de DB_URL que selecciona SQLite, por ejemplo "sqlite://chirpy.db".
+const Scheme = "sqlite://"
+
+// DriverName es el nombre del driver de database/sql que registra go-sqlite3.
+const DriverName = "sqlite3"
+
+// IsURL indica si dbURL apunta a una base de datos SQLite.
+func IsURL(dbURL string) bool {
+	return strings.HasPrefix(dbURL, Scheme)
+}
+
+// Open abre la base de datos SQLite de dbURL ("sqlite://ruta/al/archivo.db" o "sqlite://:memory:").
+// Activa las claves foráneas (necesarias para los ON DELETE CASCADE) y usa una sola conexión:
+// SQLite serializa las escrituras y así se evitan errores SQLITE_BUSY.
+func Open(dbURL string) (*sql.DB, error) {
+	if !IsURL(dbURL) {
+		return nil, fmt.Errorf("not a SQLite URL: %q", dbURL)
+	}
+	path, query, _ := strings.Cut(strings.TrimPrefix(dbURL, Scheme), "?")
+	if path == "" {
+		return nil, errors.New("SQLite URL is missing the database path")
+	}
+
+	dsn := "file:" + path + "?_foreign_keys=on&_busy_timeout=5000"
+	if query != "" {
+		dsn += "&" + query
+	}
+
+	db, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// classifyError envuelve los errores de restricción de SQLite con los mismos errores
+// que database.ClassifyError usa para Postgres.
+func classifyError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code != sqlite3.ErrConstraint {
+		return err
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return fmt.Errorf("%w: %w", database.ErrConflict, err)
+	case sqlite3.ErrConstraintForeignKey:
+		return fmt.Errorf("%w: %w", database.ErrReference, err)
+	case sqlite3.ErrConstraintCheck, sqlite3.ErrConstraintNotNull:
+		return fmt.Errorf("%w: %w", database.ErrConstraint, err)
+	}
+	return err
+}
@@ -0,0 +1,604 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+	"github.com/google/uuid"
+)
+
+// Store adapta las consultas de SQLite a los tipos del paquete database, para que los
+// handlers las usen igual que las de Postgres. Genera los UUID y las fechas que en
+// Postgres resuelven gen_random_uuid() y NOW(); todas las fechas se guardan en UTC
+// para que las comparaciones de texto de SQLite sean cronológicas.
+type Store struct {
+	q  *Queries
+	db *sql.DB
+}
+
+// NewStore crea un Store sobre db.
+func NewStore(db *sql.DB) *Store {
+	return &Store{q: New(db), db: db}
+}
+
+// InTx ejecuta fn en una transacción. Open usa una sola conexión, así que las
+// transacciones ya se serializan y no hace falta pedir otro nivel de aislamiento.
+func (s *Store) InTx(ctx context.Context, fn func(q service.Queries) error) error {
+	return service.RunTx(ctx, s.db, nil, func(tx *sql.Tx) error {
+		return fn(&Store{q: s.q.WithTx(tx)})
+	})
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+func utc(t sql.NullTime) sql.NullTime {
+	if t.Valid {
+		t.Time = t.Time.UTC()
+	}
+	return t
+}
+
+// convertAll aplica convert a cada fila de una consulta :many.
+func convertAll[T, U any](rows []T, err error, convert func(T) U) ([]U, error) {
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	out := make([]U, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, convert(row))
+	}
+	return out, nil
+}
+
+func toUser(u User) database.User                          { return database.User(u) }
+func toChirp(c Chirp) database.Chirp                       { return database.Chirp(c) }
+func toRefreshToken(rt RefreshToken) database.RefreshToken { return database.RefreshToken(rt) }
+func toReport(r Report) database.Report                    { return database.Report(r) }
+func toAuditLog(l AdminAuditLog) database.AdminAuditLog    { return database.AdminAuditLog(l) }
+func toBlock(b Block) database.Block                       { return database.Block(b) }
+func toMute(m Mute) database.Mute                          { return database.Mute(m) }
+func toLike(l Like) database.Like                          { return database.Like(l) }
+func toFollow(f Follow) database.Follow                    { return database.Follow(f) }
+func toSubscriptionEvent(e SubscriptionEvent) database.SubscriptionEvent {
+	return database.SubscriptionEvent(e)
+}
+func toNotificationPreference(p NotificationPreference) database.NotificationPreference {
+	return database.NotificationPreference(p)
+}
+
+// toNotification convierte actor_count, que SQLite lee como int64.
+func toNotification(n Notification) database.Notification {
+	return database.Notification{
+		ID:         n.ID,
+		UserID:     n.UserID,
+		Type:       n.Type,
+		GroupKey:   n.GroupKey,
+		ChirpID:    n.ChirpID,
+		ActorID:    n.ActorID,
+		ActorCount: int32(n.ActorCount),
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+		ReadAt:     n.ReadAt,
+	}
+}
+
+func notification(n Notification, err error) (database.Notification, error) {
+	return toNotification(n), classifyError(err)
+}
+
+func user(u User, err error) (database.User, error) {
+	return database.User(u), classifyError(err)
+}
+
+func chirp(c Chirp, err error) (database.Chirp, error) {
+	return database.Chirp(c), classifyError(err)
+}
+
+// Usuarios
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	return user(s.q.CreateUser(ctx, CreateUserParams{
+		ID:             uuid.New(),
+		Now:            now(),
+		Email:          arg.Email,
+		HashedPassword: arg.HashedPassword,
+		Username:       arg.Username,
+		DisplayName:    arg.DisplayName,
+	}))
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return user(s.q.GetUserByID(ctx, id))
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (database.User, error) {
+	return user(s.q.GetUserByEmail(ctx, email))
+}
+
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (database.User, error) {
+	return user(s.q.GetUserByUsername(ctx, username))
+}
+
+func (s *Store) ListUsers(ctx context.Context, arg database.ListUsersParams) ([]database.User, error) {
+	rows, err := s.q.ListUsers(ctx, ListUsersParams{
+		Query:     arg.Query,
+		RowLimit:  int64(arg.RowLimit),
+		RowOffset: int64(arg.RowOffset),
+	})
+	return convertAll(rows, err, toUser)
+}
+
+func (s *Store) UpdateUser(ctx context.Context, arg database.UpdateUserParams) (database.User, error) {
+	return user(s.q.UpdateUser(ctx, UpdateUserParams{
+		Email:          arg.Email,
+		HashedPassword: arg.HashedPassword,
+		Now:            now(),
+		ID:             arg.ID,
+	}))
+}
+
+func (s *Store) UpdateUserEmail(ctx context.Context, arg database.UpdateUserEmailParams) (database.User, error) {
+	return user(s.q.UpdateUserEmail(ctx, UpdateUserEmailParams{Email: arg.Email, Now: now(), ID: arg.ID}))
+}
+
+func (s *Store) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
+	return user(s.q.UpdateUserProfile(ctx, UpdateUserProfileParams{
+		Username:    arg.Username,
+		DisplayName: arg.DisplayName,
+		Bio:         arg.Bio,
+		AvatarUrl:   arg.AvatarUrl,
+		Now:         now(),
+		ID:          arg.ID,
+	}))
+}
+
+func (s *Store) PatchUser(ctx context.Context, arg database.PatchUserParams) (database.User, error) {
+	return user(s.q.PatchUser(ctx, PatchUserParams{
+		HashedPassword: arg.HashedPassword,
+		Username:       arg.Username,
+		DisplayName:    arg.DisplayName,
+		Bio:            arg.Bio,
+		AvatarUrl:      arg.AvatarUrl,
+		Now:            now(),
+		ID:             arg.ID,
+	}))
+}
+
+func (s *Store) UpgradeToChirpyRed(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return user(s.q.UpgradeToChirpyRed(ctx, UpgradeToChirpyRedParams{Now: now(), ID: id}))
+}
+
+func (s *Store) SetChirpyRed(ctx context.Context, arg database.SetChirpyRedParams) (database.User, error) {
+	return user(s.q.SetChirpyRed(ctx, SetChirpyRedParams{IsChirpyRed: arg.IsChirpyRed, Now: now(), ID: arg.ID}))
+}
+
+func (s *Store) SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (database.User, error) {
+	return user(s.q.SetUserRole(ctx, SetUserRoleParams{Role: arg.Role, Now: now(), ID: arg.ID}))
+}
+
+func (s *Store) SuspendUser(ctx context.Context, arg database.SuspendUserParams) (database.User, error) {
+	return user(s.q.SuspendUser(ctx, SuspendUserParams{SuspendedUntil: utc(arg.SuspendedUntil), Now: now(), ID: arg.ID}))
+}
+
+func (s *Store) BanUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return user(s.q.BanUser(ctx, BanUserParams{Now: now(), ID: id}))
+}
+
+func (s *Store) ReinstateUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return user(s.q.ReinstateUser(ctx, ReinstateUserParams{Now: now(), ID: id}))
+}
+
+func (s *Store) SoftDeleteUser(ctx context.Context, arg database.SoftDeleteUserParams) (database.User, error) {
+	return user(s.q.SoftDeleteUser(ctx, SoftDeleteUserParams{Now: now(), PurgeAfter: utc(arg.PurgeAfter), ID: arg.ID}))
+}
+
+func (s *Store) CancelUserDeletion(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return user(s.q.CancelUserDeletion(ctx, CancelUserDeletionParams{Now: now(), ID: id}))
+}
+
+// PurgeDeletedUsers elimina definitivamente las cuentas cuyo periodo de gracia terminó.
+func (s *Store) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	purged, err := s.q.PurgeDeletedUsers(ctx, now())
+	return purged, classifyError(err)
+}
+
+func (s *Store) Reset(ctx context.Context) error {
+	return classifyError(s.q.Reset(ctx))
+}
+
+// Chirps
+
+func (s *Store) CreateChirp(ctx context.Context, arg database.CreateChirpParams) (database.Chirp, error) {
+	return chirp(s.q.CreateChirp(ctx, CreateChirpParams{ID: uuid.New(), Now: now(), Body: arg.Body, UserID: arg.UserID, ReplyToID: arg.ReplyToID}))
+}
+
+func (s *Store) GetChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+	return chirp(s.q.GetChirp(ctx, id))
+}
+
+func (s *Store) GetChirpWithAuthor(ctx context.Context, id uuid.UUID) (database.GetChirpWithAuthorRow, error) {
+	row, err := s.q.GetChirpWithAuthor(ctx, id)
+	return database.GetChirpWithAuthorRow{
+		Chirp:       database.Chirp(row.Chirp),
+		Username:    row.Username,
+		DisplayName: row.DisplayName,
+		AvatarUrl:   row.AvatarUrl,
+	}, classifyError(err)
+}
+
+func (s *Store) GetChirps(ctx context.Context) ([]database.GetChirpsRow, error) {
+	rows, err := s.q.GetChirps(ctx)
+	return convertAll(rows, err, func(row GetChirpsRow) database.GetChirpsRow {
+		return database.GetChirpsRow{
+			Chirp:       database.Chirp(row.Chirp),
+			Username:    row.Username,
+			DisplayName: row.DisplayName,
+			AvatarUrl:   row.AvatarUrl,
+		}
+	})
+}
+
+func (s *Store) GetChirpsForViewer(ctx context.Context, viewerID uuid.UUID) ([]database.GetChirpsForViewerRow, error) {
+	rows, err := s.q.GetChirpsForViewer(ctx, viewerID)
+	return convertAll(rows, err, func(row GetChirpsForViewerRow) database.GetChirpsForViewerRow {
+		return database.GetChirpsForViewerRow{
+			Chirp:       database.Chirp(row.Chirp),
+			Username:    row.Username,
+			DisplayName: row.DisplayName,
+			AvatarUrl:   row.AvatarUrl,
+		}
+	})
+}
+
+func (s *Store) ListChirpsByUser(ctx context.Context, userID uuid.UUID) ([]database.Chirp, error) {
+	rows, err := s.q.ListChirpsByUser(ctx, userID)
+	return convertAll(rows, err, toChirp)
+}
+
+func (s *Store) HideChirp(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+	return chirp(s.q.HideChirp(ctx, HideChirpParams{Now: now(), ID: id}))
+}
+
+func (s *Store) DeleteChirp(ctx context.Context, id uuid.UUID) error {
+	return classifyError(s.q.DeleteChirp(ctx, id))
+}
+
+// Refresh tokens y verificación de email
+
+func (s *Store) CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (database.RefreshToken, error) {
+	rt, err := s.q.CreateRefreshToken(ctx, CreateRefreshTokenParams{
+		Token:     arg.Token,
+		Now:       now(),
+		UserID:    arg.UserID,
+		ExpiresAt: arg.ExpiresAt.UTC(),
+	})
+	return database.RefreshToken(rt), classifyError(err)
+}
+
+func (s *Store) GetUserFromRefreshToken(ctx context.Context, token string) (database.User, error) {
+	return user(s.q.GetUserFromRefreshToken(ctx, GetUserFromRefreshTokenParams{Token: token, Now: now()}))
+}
+
+func (s *Store) ListRefreshTokensForUser(ctx context.Context, userID uuid.UUID) ([]database.RefreshToken, error) {
+	rows, err := s.q.ListRefreshTokensForUser(ctx, userID)
+	return convertAll(rows, err, toRefreshToken)
+}
+
+func (s *Store) RevokeRefreshToken(ctx context.Context, token string) (database.RefreshToken, error) {
+	rt, err := s.q.RevokeRefreshToken(ctx, RevokeRefreshTokenParams{Now: now(), Token: token})
+	return database.RefreshToken(rt), classifyError(err)
+}
+
+func (s *Store) RevokeAllRefreshTokensForUser(ctx context.Context, userID uuid.UUID) error {
+	return classifyError(s.q.RevokeAllRefreshTokensForUser(ctx, RevokeAllRefreshTokensForUserParams{Now: now(), UserID: userID}))
+}
+
+func (s *Store) CreateEmailVerification(ctx context.Context, arg database.CreateEmailVerificationParams) (database.EmailVerification, error) {
+	ev, err := s.q.CreateEmailVerification(ctx, CreateEmailVerificationParams{
+		Token:     arg.Token,
+		Now:       now(),
+		UserID:    arg.UserID,
+		Email:     arg.Email,
+		ExpiresAt: arg.ExpiresAt.UTC(),
+	})
+	return database.EmailVerification(ev), classifyError(err)
+}
+
+func (s *Store) GetPendingEmailVerification(ctx context.Context, token string) (database.EmailVerification, error) {
+	ev, err := s.q.GetPendingEmailVerification(ctx, GetPendingEmailVerificationParams{Token: token, Now: now()})
+	return database.EmailVerification(ev), classifyError(err)
+}
+
+func (s *Store) MarkEmailVerificationUsed(ctx context.Context, token string) error {
+	return classifyError(s.q.MarkEmailVerificationUsed(ctx, MarkEmailVerificationUsedParams{Now: now(), Token: token}))
+}
+
+// Bloqueos y silenciados
+
+func (s *Store) BlockUser(ctx context.Context, arg database.BlockUserParams) error {
+	return classifyError(s.q.BlockUser(ctx, BlockUserParams{BlockerID: arg.BlockerID, BlockedID: arg.BlockedID, Now: now()}))
+}
+
+func (s *Store) UnblockUser(ctx context.Context, arg database.UnblockUserParams) error {
+	return classifyError(s.q.UnblockUser(ctx, UnblockUserParams(arg)))
+}
+
+func (s *Store) IsBlocked(ctx context.Context, arg database.IsBlockedParams) (bool, error) {
+	blocked, err := s.q.IsBlocked(ctx, IsBlockedParams(arg))
+	return blocked != 0, classifyError(err)
+}
+
+func (s *Store) ListBlocks(ctx context.Context, blockerID uuid.UUID) ([]database.Block, error) {
+	rows, err := s.q.ListBlocks(ctx, blockerID)
+	return convertAll(rows, err, toBlock)
+}
+
+func (s *Store) ListBlockers(ctx context.Context, blockedID uuid.UUID) ([]database.Block, error) {
+	rows, err := s.q.ListBlockers(ctx, blockedID)
+	return convertAll(rows, err, toBlock)
+}
+
+func (s *Store) MuteUser(ctx context.Context, arg database.MuteUserParams) error {
+	return classifyError(s.q.MuteUser(ctx, MuteUserParams{MuterID: arg.MuterID, MutedID: arg.MutedID, Now: now()}))
+}
+
+func (s *Store) UnmuteUser(ctx context.Context, arg database.UnmuteUserParams) error {
+	return classifyError(s.q.UnmuteUser(ctx, UnmuteUserParams(arg)))
+}
+
+func (s *Store) ListMutes(ctx context.Context, muterID uuid.UUID) ([]database.Mute, error) {
+	rows, err := s.q.ListMutes(ctx, muterID)
+	return convertAll(rows, err, toMute)
+}
+
+// Likes y follows
+
+func (s *Store) LikeChirp(ctx context.Context, arg database.LikeChirpParams) (int64, error) {
+	n, err := s.q.LikeChirp(ctx, LikeChirpParams{UserID: arg.UserID, ChirpID: arg.ChirpID, Now: now()})
+	return n, classifyError(err)
+}
+
+func (s *Store) UnlikeChirp(ctx context.Context, arg database.UnlikeChirpParams) error {
+	return classifyError(s.q.UnlikeChirp(ctx, UnlikeChirpParams(arg)))
+}
+
+func (s *Store) ListLikesByUser(ctx context.Context, userID uuid.UUID) ([]database.Like, error) {
+	rows, err := s.q.ListLikesByUser(ctx, userID)
+	return convertAll(rows, err, toLike)
+}
+
+func (s *Store) FollowUser(ctx context.Context, arg database.FollowUserParams) (int64, error) {
+	n, err := s.q.FollowUser(ctx, FollowUserParams{FollowerID: arg.FollowerID, FollowedID: arg.FollowedID, Now: now()})
+	return n, classifyError(err)
+}
+
+func (s *Store) UnfollowUser(ctx context.Context, arg database.UnfollowUserParams) error {
+	return classifyError(s.q.UnfollowUser(ctx, UnfollowUserParams(arg)))
+}
+
+func (s *Store) ListFollowing(ctx context.Context, followerID uuid.UUID) ([]database.Follow, error) {
+	rows, err := s.q.ListFollowing(ctx, followerID)
+	return convertAll(rows, err, toFollow)
+}
+
+func (s *Store) DeleteFollowsBetween(ctx context.Context, arg database.DeleteFollowsBetweenParams) error {
+	return classifyError(s.q.DeleteFollowsBetween(ctx, DeleteFollowsBetweenParams(arg)))
+}
+
+func (s *Store) ListFollowers(ctx context.Context, followedID uuid.UUID) ([]database.Follow, error) {
+	rows, err := s.q.ListFollowers(ctx, followedID)
+	return convertAll(rows, err, toFollow)
+}
+
+// Moderación, auditoría y suscripciones
+
+func (s *Store) CreateReport(ctx context.Context, arg database.CreateReportParams) (database.Report, error) {
+	report, err := s.q.CreateReport(ctx, CreateReportParams{
+		ID:             uuid.New(),
+		Now:            now(),
+		ReporterID:     arg.ReporterID,
+		ReportedUserID: arg.ReportedUserID,
+		ChirpID:        arg.ChirpID,
+		Reason:         arg.Reason,
+		Details:        arg.Details,
+	})
+	return database.Report(report), classifyError(err)
+}
+
+func (s *Store) GetReport(ctx context.Context, id uuid.UUID) (database.Report, error) {
+	report, err := s.q.GetReport(ctx, id)
+	return database.Report(report), classifyError(err)
+}
+
+func (s *Store) ListReports(ctx context.Context, arg database.ListReportsParams) ([]database.Report, error) {
+	rows, err := s.q.ListReports(ctx, ListReportsParams{
+		Status: arg.Status,
+		Limit:  int64(arg.Limit),
+		Offset: int64(arg.Offset),
+	})
+	return convertAll(rows, err, toReport)
+}
+
+func (s *Store) ResolveReport(ctx context.Context, arg database.ResolveReportParams) (database.Report, error) {
+	report, err := s.q.ResolveReport(ctx, ResolveReportParams{
+		Resolution: arg.Resolution,
+		ResolvedBy: arg.ResolvedBy,
+		Now:        now(),
+		ID:         arg.ID,
+	})
+	return database.Report(report), classifyError(err)
+}
+
+func (s *Store) CreateAuditLog(ctx context.Context, arg database.CreateAuditLogParams) (database.AdminAuditLog, error) {
+	entry, err := s.q.CreateAuditLog(ctx, CreateAuditLogParams{
+		ID:         uuid.New(),
+		Now:        now(),
+		ActorID:    arg.ActorID,
+		Action:     arg.Action,
+		TargetType: arg.TargetType,
+		TargetID:   arg.TargetID,
+		Details:    arg.Details,
+	})
+	return database.AdminAuditLog(entry), classifyError(err)
+}
+
+func (s *Store) ListAuditLogs(ctx context.Context, arg database.ListAuditLogsParams) ([]database.AdminAuditLog, error) {
+	rows, err := s.q.ListAuditLogs(ctx, ListAuditLogsParams{Limit: int64(arg.Limit), Offset: int64(arg.Offset)})
+	return convertAll(rows, err, toAuditLog)
+}
+
+func (s *Store) CreateSubscriptionEvent(ctx context.Context, arg database.CreateSubscriptionEventParams) (database.SubscriptionEvent, error) {
+	event, err := s.q.CreateSubscriptionEvent(ctx, CreateSubscriptionEventParams{
+		ID:     uuid.New(),
+		Now:    now(),
+		UserID: arg.UserID,
+		Event:  arg.Event,
+		Source: arg.Source,
+	})
+	return database.SubscriptionEvent(event), classifyError(err)
+}
+
+func (s *Store) ListSubscriptionEvents(ctx context.Context, userID uuid.UUID) ([]database.SubscriptionEvent, error) {
+	rows, err := s.q.ListSubscriptionEvents(ctx, userID)
+	return convertAll(rows, err, toSubscriptionEvent)
+}
+
+// Claves de idempotencia
+
+func (s *Store) CreateIdempotencyKey(ctx context.Context, arg database.CreateIdempotencyKeyParams) (int64, error) {
+	created, err := s.q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Scope:       arg.Scope,
+		Key:         arg.Key,
+		Fingerprint: arg.Fingerprint,
+		Now:         now(),
+		ExpiresAt:   arg.ExpiresAt.UTC(),
+	})
+	return created, classifyError(err)
+}
+
+func (s *Store) GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	k, err := s.q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{Scope: arg.Scope, Key: arg.Key, Now: now()})
+	return database.IdempotencyKey{
+		Scope:        k.Scope,
+		Key:          k.Key,
+		Fingerprint:  k.Fingerprint,
+		StatusCode:   int32(k.StatusCode),
+		ContentType:  k.ContentType,
+		ResponseBody: k.ResponseBody,
+		CreatedAt:    k.CreatedAt,
+		ExpiresAt:    k.ExpiresAt,
+	}, classifyError(err)
+}
+
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, arg database.CompleteIdempotencyKeyParams) error {
+	return classifyError(s.q.CompleteIdempotencyKey(ctx, CompleteIdempotencyKeyParams{
+		StatusCode:   int64(arg.StatusCode),
+		ContentType:  arg.ContentType,
+		ResponseBody: arg.ResponseBody,
+		ExpiresAt:    arg.ExpiresAt.UTC(),
+		Scope:        arg.Scope,
+		Key:          arg.Key,
+	}))
+}
+
+func (s *Store) DeleteIdempotencyKey(ctx context.Context, arg database.DeleteIdempotencyKeyParams) error {
+	return classifyError(s.q.DeleteIdempotencyKey(ctx, DeleteIdempotencyKeyParams(arg)))
+}
+
+func (s *Store) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	purged, err := s.q.PurgeExpiredIdempotencyKeys(ctx, now())
+	return purged, classifyError(err)
+}
+
+// Notificaciones
+
+func (s *Store) GetUnreadNotificationGroup(ctx context.Context, arg database.GetUnreadNotificationGroupParams) (database.Notification, error) {
+	return notification(s.q.GetUnreadNotificationGroup(ctx, GetUnreadNotificationGroupParams{
+		UserID:   arg.UserID,
+		Type:     arg.Type,
+		GroupKey: arg.GroupKey,
+		Since:    arg.Since.UTC(),
+	}))
+}
+
+func (s *Store) CreateNotification(ctx context.Context, arg database.CreateNotificationParams) (database.Notification, error) {
+	return notification(s.q.CreateNotification(ctx, CreateNotificationParams{
+		ID:       uuid.New(),
+		UserID:   arg.UserID,
+		Type:     arg.Type,
+		GroupKey: arg.GroupKey,
+		ChirpID:  arg.ChirpID,
+		ActorID:  arg.ActorID,
+		Now:      now(),
+	}))
+}
+
+func (s *Store) AddNotificationActor(ctx context.Context, arg database.AddNotificationActorParams) (int64, error) {
+	added, err := s.q.AddNotificationActor(ctx, AddNotificationActorParams{
+		NotificationID: arg.NotificationID,
+		ActorID:        arg.ActorID,
+		Now:            now(),
+	})
+	return added, classifyError(err)
+}
+
+func (s *Store) BumpNotification(ctx context.Context, arg database.BumpNotificationParams) (database.Notification, error) {
+	return notification(s.q.BumpNotification(ctx, BumpNotificationParams{
+		ChirpID:   arg.ChirpID,
+		ActorID:   arg.ActorID,
+		NewActors: int64(arg.NewActors),
+		Now:       now(),
+		ID:        arg.ID,
+	}))
+}
+
+func (s *Store) ListNotifications(ctx context.Context, arg database.ListNotificationsParams) ([]database.ListNotificationsRow, error) {
+	rows, err := s.q.ListNotifications(ctx, ListNotificationsParams{
+		UserID:     arg.UserID,
+		UnreadOnly: arg.UnreadOnly,
+		RowLimit:   int64(arg.RowLimit),
+		RowOffset:  int64(arg.RowOffset),
+	})
+	return convertAll(rows, err, func(row ListNotificationsRow) database.ListNotificationsRow {
+		return database.ListNotificationsRow{
+			Notification: toNotification(row.Notification),
+			Username:     row.Username,
+			DisplayName:  row.DisplayName,
+			AvatarUrl:    row.AvatarUrl,
+		}
+	})
+}
+
+func (s *Store) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := s.q.CountUnreadNotifications(ctx, userID)
+	return count, classifyError(err)
+}
+
+func (s *Store) MarkNotificationRead(ctx context.Context, arg database.MarkNotificationReadParams) (database.Notification, error) {
+	return notification(s.q.MarkNotificationRead(ctx, MarkNotificationReadParams{Now: now(), ID: arg.ID, UserID: arg.UserID}))
+}
+
+func (s *Store) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	read, err := s.q.MarkAllNotificationsRead(ctx, MarkAllNotificationsReadParams{Now: now(), UserID: userID})
+	return read, classifyError(err)
+}
+
+func (s *Store) ListNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	rows, err := s.q.ListNotificationPreferences(ctx, userID)
+	return convertAll(rows, err, toNotificationPreference)
+}
+
+func (s *Store) SetNotificationPreference(ctx context.Context, arg database.SetNotificationPreferenceParams) (database.NotificationPreference, error) {
+	pref, err := s.q.SetNotificationPreference(ctx, SetNotificationPreferenceParams{
+		UserID:  arg.UserID,
+		Type:    arg.Type,
+		Enabled: arg.Enabled,
+		Now:     now(),
+	})
+	return database.NotificationPreference(pref), classifyError(err)
+}
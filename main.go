@@ -1,48 +1,61 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/amadrigalIstmo/Chirpy-project/api"
 	"github.com/amadrigalIstmo/Chirpy-project/handler"
-	"github.com/amadrigalIstmo/Chirpy-project/internal/database"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/cache"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/events"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/health"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/metrics"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/middleware"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/migrate"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
-type apiConfig struct {
-	DB        *database.Queries
-	Platform  string
-	JWTSecret string
-	PolkaKey  string // 🔹 Agregamos el campo para almacenar la API Key de Polka
-}
-
 func main() {
-	err := godotenv.Load()
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		fmt.Fprintln(os.Stderr, "Invalid configuration:", err)
+		os.Exit(2)
 	}
 
-	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
-		log.Fatal("DB_URL is not set in the environment variables")
+	// 🔹 Subcomandos como "chirpy migrate up"
+	if len(args) > 0 {
+		os.Exit(runCommand(cfg, args))
 	}
 
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET is not set in the environment variables")
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid configuration:")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
 
-	polkaKey := os.Getenv("POLKA_KEY") // 🔹 Cargamos la API Key de Polka
-	if polkaKey == "" {
-		log.Fatal("POLKA_KEY is not set in the environment variables")
-	}
+	// 🔹 Logs JSON con emails y tokens redactados; log.Printf también pasa por aquí
+	var logLevel slog.Level
+	logLevel.UnmarshalText([]byte(cfg.LogLevel)) // ya validado por config
+	logger := middleware.NewLogger(os.Stdout, logLevel)
+	slog.SetDefault(logger)
+	slog.Info("effective configuration", slog.Any("config", cfg.Redacted()))
 
-	db, err := sql.Open("postgres", dbURL)
+	db, dialect, err := openDatabase(cfg.Database)
 	if err != nil {
 		log.Fatal("Could not connect to database:", err)
 	}
@@ -52,37 +65,118 @@ func main() {
 		log.Fatal("Could not ping database:", err)
 	}
 
-	apiCfg := apiConfig{
-		DB:        database.New(db),
-		Platform:  os.Getenv("PLATFORM"),
-		JWTSecret: jwtSecret,
-		PolkaKey:  polkaKey, // 🔹 Guardamos la API Key de Polka en apiConfig
+	// 🔹 Migraciones: opcionalmente se aplican al arrancar (con advisory lock entre réplicas),
+	// y nunca se sirve tráfico con el esquema atrasado
+	provider, err := migrate.NewProvider(db, dialect)
+	if err != nil {
+		log.Fatal("Could not load migrations:", err)
+	}
+	if cfg.Database.AutoMigrate {
+		results, err := provider.Up(context.Background())
+		for _, result := range results {
+			slog.Info("migration applied",
+				slog.Int64("version", result.Source.Version),
+				slog.String("source", result.Source.Path),
+				slog.Duration("duration", result.Duration))
+		}
+		if err != nil {
+			log.Fatal("Could not apply migrations:", err)
+		}
+	}
+	if err := migrate.EnsureCurrent(context.Background(), provider); err != nil {
+		log.Fatal("Refusing to serve: ", err)
+	}
+
+	dbStore := newStore(db, dialect)
+	if cfg.Features.Cache {
+		dbStore = cache.NewStore(dbStore, cache.NewLRU(cfg.Cache.Size), cfg.Cache.TTL)
+	}
+	handlers := handler.NewHandler(dbStore, cfg)
+
+	// 🔹 Eventos en tiempo real: con Postgres viajan entre réplicas por LISTEN/NOTIFY;
+	// con SQLite hay una sola réplica y basta el broker local
+	var eventFanout *events.PostgresFanout
+	if dialect == migrate.DialectPostgres {
+		eventFanout = events.NewPostgresFanout(db, cfg.Database.URL, handlers.Events(), dbStore.GetChirp)
+		handlers.Events().SetFanout(eventFanout)
 	}
 
-	// 🔹 Pasamos polkaKey al crear el Handler
-	handlers := handler.NewHandler(apiCfg.DB, apiCfg.Platform, apiCfg.JWTSecret, apiCfg.PolkaKey)
+	// 🔹 Liveness y readiness
+	checker := health.New(2 * time.Second)
+	checker.AddCheck("database", health.DatabaseCheck(db))
+	checker.AddCheck("migrations", health.MigrationsCheck(db, migrate.LatestVersion(provider)))
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("POST /api/users", handlers.CreateUser)
-	mux.HandleFunc("POST /api/chirps", handlers.CreateChirp)
-	mux.HandleFunc("GET /api/chirps", handlers.PolkaGetChirps)
-	mux.HandleFunc("POST /admin/reset", handlers.ResetDatabase)
-	mux.HandleFunc("GET /api/chirps/{chirpID}", handlers.GetChirpByID)
-	mux.HandleFunc("POST /api/polka/webhooks", handlers.PolkaWebhook)
-	mux.HandleFunc("POST /api/login", handlers.Login)
-	mux.HandleFunc("POST /api/refresh", handlers.RefreshTokenHandler)
-	mux.HandleFunc("POST /api/revoke", handlers.RevokeTokenHandler)
-	mux.HandleFunc("PUT /api/users", handlers.UpdateUser)
-	mux.HandleFunc("DELETE /api/chirps/{chirpID}", handlers.DeleteChirp)
-
-	server := &http.Server{
-		Addr:    ":8080",
-		Handler: mux,
+	mux.HandleFunc("GET /healthz", checker.Liveness)
+	mux.HandleFunc("GET /readyz", checker.Readiness)
+	handlers.RegisterRoutes(mux)
+
+	// 🔹 Métricas de Prometheus: en un listener aparte si metrics.addr está definido,
+	// si no, solo para administradores
+	var servers []*http.Server
+	if cfg.Features.Metrics {
+		metrics.RegisterDB(db)
+		if cfg.Metrics.Addr != "" {
+			metricsMux := http.NewServeMux()
+			metricsMux.Handle("GET /metrics", metrics.Handler())
+			servers = append(servers, newHTTPServer(cfg.Server, cfg.Metrics.Addr, metricsMux))
+		} else {
+			mux.HandleFunc("GET /admin/metrics", handlers.Metrics)
+		}
 	}
 
-	fmt.Println("Servidor corriendo en http://localhost:8080")
-	err = server.ListenAndServe()
-	if err != nil {
-		log.Fatal("Error al iniciar el servidor:", err)
+	// 🔹 SIGINT/SIGTERM cancelan ctx: se detienen los workers y empieza el apagado.
+	// Una segunda señal mata el proceso sin esperar.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	// 🔹 Purga periódica de cuentas eliminadas y de claves de idempotencia expiradas
+	var workers sync.WaitGroup
+	if cfg.Features.AccountPurger {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			runAccountPurger(ctx, dbStore, cfg.Accounts.PurgeInterval)
+		}()
+	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		runIdempotencyKeyPurger(ctx, dbStore, idempotencyKeyPurgeInterval)
+	}()
+	if eventFanout != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := eventFanout.Run(ctx); err != nil {
+				slog.Error("event listener stopped", slog.Any("err", err))
+			}
+		}()
+	}
+
+	// Los streams abiertos terminan al empezar el apagado; los clientes se reconectan a otra réplica
+	context.AfterFunc(ctx, handlers.Events().Close)
+
+	// 🔹 Middlewares: request ID → access log → métricas → recuperación de panics → rutas
+	app := middleware.Chain(mux,
+		api.WithRequestID,
+		middleware.AccessLog(logger),
+		middleware.Metrics,
+		middleware.Recover(logger),
+	)
+
+	servers = append(servers, newHTTPServer(cfg.Server, cfg.Server.Addr, app))
+
+	fmt.Println("Servidor corriendo en http://localhost" + cfg.Server.Addr)
+	serveErr := serve(ctx, cfg.Server, checker, servers...)
+
+	// Si el servidor falló al arrancar, ctx sigue vivo: lo cancelamos para detener los workers
+	stop()
+	workers.Wait()
+
+	if serveErr != nil {
+		log.Fatal("Error al iniciar el servidor:", serveErr)
 	}
+	slog.Info("server stopped")
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/service"
+)
+
+// idempotencyKeyPurgeInterval es cada cuánto se borran las claves de idempotencia expiradas.
+const idempotencyKeyPurgeInterval = time.Hour
+
+// runAccountPurger elimina definitivamente, cada intervalo, las cuentas cuyo periodo de gracia terminó.
+// Los chirps y refresh tokens se borran en cascada (ON DELETE CASCADE).
+func runAccountPurger(ctx context.Context, db service.Queries, interval time.Duration) {
+	runPeriodically(ctx, interval, func() {
+		purged, err := db.PurgeDeletedUsers(ctx)
+		if err != nil {
+			log.Printf("Error purging deleted accounts: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d deleted accounts", purged)
+		}
+	})
+}
+
+// runIdempotencyKeyPurger borra, cada intervalo, las respuestas guardadas cuya clave de
+// idempotencia expiró. Las consultas ya ignoran las expiradas; esto solo libera espacio.
+func runIdempotencyKeyPurger(ctx context.Context, db service.Queries, interval time.Duration) {
+	runPeriodically(ctx, interval, func() {
+		if _, err := db.PurgeExpiredIdempotencyKeys(ctx); err != nil {
+			log.Printf("Error purging expired idempotency keys: %v", err)
+		}
+	})
+}
+
+// runPeriodically ejecuta fn enseguida y luego cada intervalo, hasta que ctx se cancele.
+func runPeriodically(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
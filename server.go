@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/amadrigalIstmo/Chirpy-project/internal/config"
+	"github.com/amadrigalIstmo/Chirpy-project/internal/health"
+)
+
+func newHTTPServer(cfg config.ServerConfig, addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// serve arranca los servidores y, cuando ctx se cancela (SIGINT/SIGTERM), hace el apagado:
+// marca /readyz como fallando, espera DrainDelay y llama a Shutdown con ShutdownTimeout.
+// Si algún servidor no puede arrancar, apaga el resto y devuelve el error.
+func serve(ctx context.Context, cfg config.ServerConfig, checker *health.Checker, servers ...*http.Server) error {
+	errCh := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			slog.Info("listening", slog.String("addr", server.Addr))
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("listen on %s: %w", server.Addr, err)
+			}
+		}()
+	}
+
+	var serveErr error
+	select {
+	case serveErr = <-errCh:
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining", slog.Duration("drain_delay", cfg.DrainDelay))
+		checker.SetDraining()
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				// Se acabó el plazo: se cortan las conexiones que quedan
+				slog.Error("graceful shutdown timed out, closing connections",
+					slog.String("addr", server.Addr), slog.Any("err", err))
+				server.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return serveErr
+}
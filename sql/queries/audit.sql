@@ -0,0 +1,17 @@
+-- name: CreateAuditLog :one
+INSERT INTO admin_audit_log (id, created_at, actor_id, action, target_type, target_id, details)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: ListAuditLogs :many
+SELECT * FROM admin_audit_log
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
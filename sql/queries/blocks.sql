@@ -0,0 +1,24 @@
+-- name: BlockUser :exec
+INSERT INTO blocks (blocker_id, blocked_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT DO NOTHING;
+
+-- name: UnblockUser :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: ListBlocks :many
+SELECT * FROM blocks
+WHERE blocker_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListBlockers :many
+SELECT * FROM blocks
+WHERE blocked_id = $1
+ORDER BY created_at DESC;
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+);
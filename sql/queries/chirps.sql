@@ -1,22 +1,55 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, reply_to_id)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3
 )
 RETURNING *;
 
 -- name: GetChirps :many
-SELECT * FROM chirps
-ORDER BY created_at ASC;
+SELECT sqlc.embed(chirps), users.username, users.display_name, users.avatar_url FROM chirps
+JOIN users ON users.id = chirps.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY chirps.created_at ASC;
 
 -- name: GetChirp :one
 SELECT * FROM chirps
 WHERE id = $1;
 
+-- name: GetChirpWithAuthor :one
+SELECT sqlc.embed(chirps), users.username, users.display_name, users.avatar_url FROM chirps
+JOIN users ON users.id = chirps.user_id
+WHERE chirps.id = $1 AND users.deleted_at IS NULL;
+
 -- name: DeleteChirp :exec
 DELETE FROM chirps
 WHERE id = $1;
+
+-- name: HideChirp :one
+UPDATE chirps SET hidden_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetChirpsForViewer :many
+SELECT sqlc.embed(chirps), users.username, users.display_name, users.avatar_url FROM chirps
+JOIN users ON users.id = chirps.user_id
+WHERE users.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.arg(viewer_id) AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocks.blocker_id = sqlc.arg(viewer_id) AND blocks.blocked_id = chirps.user_id)
+    OR (blocks.blocker_id = chirps.user_id AND blocks.blocked_id = sqlc.arg(viewer_id))
+)
+ORDER BY chirps.created_at ASC;
+
+-- name: ListChirpsByUser :many
+SELECT * FROM chirps
+WHERE user_id = $1
+ORDER BY created_at ASC;
@@ -0,0 +1,20 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (token, created_at, user_id, email, expires_at)
+VALUES (
+    $1,
+    NOW(),
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: GetPendingEmailVerification :one
+SELECT * FROM email_verifications
+WHERE token = $1
+AND used_at IS NULL
+AND expires_at > NOW();
+
+-- name: MarkEmailVerificationUsed :exec
+UPDATE email_verifications SET used_at = NOW()
+WHERE token = $1;
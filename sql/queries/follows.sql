@@ -0,0 +1,23 @@
+-- name: FollowUser :execrows
+INSERT INTO follows (follower_id, followed_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT DO NOTHING;
+
+-- name: UnfollowUser :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followed_id = $2;
+
+-- name: ListFollowing :many
+SELECT * FROM follows
+WHERE follower_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListFollowers :many
+SELECT * FROM follows
+WHERE followed_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg(user_a) AND followed_id = sqlc.arg(user_b))
+OR (follower_id = sqlc.arg(user_b) AND followed_id = sqlc.arg(user_a));
@@ -0,0 +1,41 @@
+-- name: CreateIdempotencyKey :execrows
+INSERT INTO idempotency_keys (scope, key, fingerprint, created_at, expires_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    NOW(),
+    $4
+)
+ON CONFLICT (scope, key) DO UPDATE
+SET fingerprint = EXCLUDED.fingerprint,
+    status_code = 0,
+    content_type = '',
+    response_body = '',
+    created_at = EXCLUDED.created_at,
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= NOW();
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE scope = $1
+AND key = $2
+AND expires_at > NOW();
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $3,
+    content_type = $4,
+    response_body = $5,
+    expires_at = $6
+WHERE scope = $1
+AND key = $2;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE scope = $1
+AND key = $2;
+
+-- name: PurgeExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= NOW();
@@ -0,0 +1,13 @@
+-- name: MuteUser :exec
+INSERT INTO mutes (muter_id, muted_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT DO NOTHING;
+
+-- name: UnmuteUser :exec
+DELETE FROM mutes
+WHERE muter_id = $1 AND muted_id = $2;
+
+-- name: ListMutes :many
+SELECT * FROM mutes
+WHERE muter_id = $1
+ORDER BY created_at DESC;
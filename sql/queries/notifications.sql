@@ -0,0 +1,71 @@
+-- name: GetUnreadNotificationGroup :one
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+AND type = sqlc.arg(type)
+AND group_key = sqlc.arg(group_key)
+AND read_at IS NULL
+AND updated_at > sqlc.arg(since)
+ORDER BY updated_at DESC
+LIMIT 1;
+
+-- name: CreateNotification :one
+INSERT INTO notifications (id, user_id, type, group_key, chirp_id, actor_id, actor_count, created_at, updated_at)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    1,
+    NOW(),
+    NOW()
+)
+RETURNING *;
+
+-- name: AddNotificationActor :execrows
+INSERT INTO notification_actors (notification_id, actor_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT DO NOTHING;
+
+-- name: BumpNotification :one
+UPDATE notifications
+SET chirp_id = sqlc.arg(chirp_id),
+    actor_id = sqlc.arg(actor_id),
+    actor_count = actor_count + sqlc.arg(new_actors),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT sqlc.embed(notifications), actors.username, actors.display_name, actors.avatar_url FROM notifications
+LEFT JOIN users AS actors ON actors.id = notifications.actor_id
+WHERE notifications.user_id = sqlc.arg(user_id)
+AND (NOT sqlc.arg(unread_only)::boolean OR notifications.read_at IS NULL)
+ORDER BY notifications.updated_at DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE user_id = $1
+ORDER BY type;
+
+-- name: SetNotificationPreference :one
+INSERT INTO notification_preferences (user_id, type, enabled, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (user_id, type) DO UPDATE
+SET enabled = excluded.enabled, updated_at = excluded.updated_at
+RETURNING *;
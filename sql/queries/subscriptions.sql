@@ -0,0 +1,15 @@
+-- name: CreateSubscriptionEvent :one
+INSERT INTO subscription_events (id, created_at, user_id, event, source)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: ListSubscriptionEvents :many
+SELECT * FROM subscription_events
+WHERE user_id = $1
+ORDER BY created_at ASC;
@@ -1,11 +1,13 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, username, display_name)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3,
+    $4
 )
 RETURNING *;
 
@@ -13,12 +15,87 @@ RETURNING *;
 SELECT * FROM users
 WHERE email = $1;
 
+-- name: GetUserByUsername :one
+SELECT * FROM users
+WHERE LOWER(username) = LOWER(sqlc.arg(username)) AND deleted_at IS NULL;
+
+-- name: UpdateUserProfile :one
+UPDATE users SET username = $2, display_name = $3, bio = $4, avatar_url = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
 -- name: UpdateUser :one
 UPDATE users SET email = $2, hashed_password = $3, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
+-- name: PatchUser :one
+UPDATE users SET
+    hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
+    username = COALESCE(sqlc.narg(username), username),
+    display_name = COALESCE(sqlc.narg(display_name), display_name),
+    bio = COALESCE(sqlc.narg(bio), bio),
+    avatar_url = COALESCE(sqlc.narg(avatar_url), avatar_url),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateUserEmail :one
+UPDATE users SET email = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
 -- name: UpgradeToChirpyRed :one
 UPDATE users SET is_chirpy_red = true, updated_at = NOW()
 WHERE id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1;
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE sqlc.arg(query)::text = ''
+   OR email ILIKE '%' || replace(replace(replace(sqlc.arg(query)::text, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+ORDER BY created_at ASC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: SetUserRole :one
+UPDATE users SET role = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SuspendUser :one
+UPDATE users SET suspended_until = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: BanUser :one
+UPDATE users SET banned_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ReinstateUser :one
+UPDATE users SET suspended_until = NULL, banned_at = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetChirpyRed :one
+UPDATE users SET is_chirpy_red = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SoftDeleteUser :one
+UPDATE users SET deleted_at = NOW(), purge_after = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CancelUserDeletion :one
+UPDATE users SET deleted_at = NULL, purge_after = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND purge_after <= NOW();
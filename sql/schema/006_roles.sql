@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN role TEXT NOT NULL DEFAULT 'user'
+CHECK (role IN ('user', 'moderator', 'admin')),
+ADD COLUMN suspended_until TIMESTAMP NULL,
+ADD COLUMN banned_at TIMESTAMP NULL;
+
+CREATE TABLE admin_audit_log (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    actor_id UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID NOT NULL,
+    details TEXT NOT NULL DEFAULT ''
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS admin_audit_log;
+
+ALTER TABLE users
+DROP COLUMN banned_at,
+DROP COLUMN suspended_until,
+DROP COLUMN role;
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN hidden_at TIMESTAMP NULL;
+
+CREATE TABLE reports (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reported_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID NULL REFERENCES chirps(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL
+    CHECK (reason IN ('spam', 'harassment', 'hate_speech', 'violence', 'sexual_content', 'misinformation', 'impersonation', 'other')),
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'resolved')),
+    resolution TEXT NULL,
+    resolved_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP NULL
+);
+
+CREATE INDEX reports_status_created_at_idx ON reports (status, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS reports;
+
+ALTER TABLE chirps
+DROP COLUMN hidden_at;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN deleted_at TIMESTAMP NULL,
+ADD COLUMN purge_after TIMESTAMP NULL;
+
+CREATE INDEX users_purge_after_idx ON users (purge_after) WHERE purge_after IS NOT NULL;
+
+CREATE TABLE subscription_events (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    source TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_events;
+
+DROP INDEX IF EXISTS users_purge_after_idx;
+
+ALTER TABLE users
+DROP COLUMN purge_after,
+DROP COLUMN deleted_at;
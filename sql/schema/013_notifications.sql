@@ -0,0 +1,40 @@
+-- +goose Up
+-- Notificaciones de cada usuario. Las que llegan seguidas y son del mismo tipo y grupo
+-- se agrupan en una sola fila mientras no se lean: actor_id es el último usuario que
+-- la provocó y actor_count cuántos distintos hubo (notification_actors).
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    group_key TEXT NOT NULL DEFAULT '',
+    chirp_id UUID NULL REFERENCES chirps(id) ON DELETE SET NULL,
+    actor_id UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    actor_count INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMP NULL
+);
+
+CREATE INDEX notifications_user_id_idx ON notifications (user_id, updated_at DESC);
+CREATE INDEX notifications_unread_idx ON notifications (user_id, type, group_key) WHERE read_at IS NULL;
+
+CREATE TABLE notification_actors (
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (notification_id, actor_id)
+);
+
+-- Sin fila para un tipo, las notificaciones de ese tipo están activadas
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, type)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notification_actors;
+DROP TABLE IF EXISTS notifications;
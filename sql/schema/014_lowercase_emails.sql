@@ -0,0 +1,11 @@
+-- +goose Up
+-- Los emails no distinguen mayúsculas: se guardan en minúsculas y el índice impide dos
+-- cuentas que solo se diferencian en eso. Si ya existen, la migración falla y hay que
+-- resolver los duplicados a mano.
+UPDATE users SET email = LOWER(email) WHERE email <> LOWER(email);
+UPDATE email_verifications SET email = LOWER(email) WHERE email <> LOWER(email);
+
+CREATE UNIQUE INDEX users_email_lower_idx ON users (LOWER(email));
+
+-- +goose Down
+DROP INDEX IF EXISTS users_email_lower_idx;
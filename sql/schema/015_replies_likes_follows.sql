@@ -0,0 +1,28 @@
+-- +goose Up
+-- Sin clave foránea a propósito: una respuesta sigue apuntando a su chirp aunque se borre,
+-- como un hilo con un mensaje eliminado, y borrar un chirp no modifica sus respuestas.
+ALTER TABLE chirps ADD COLUMN reply_to_id UUID NULL;
+CREATE INDEX chirps_reply_to_id_idx ON chirps (reply_to_id);
+
+CREATE TABLE likes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, chirp_id)
+);
+
+CREATE TABLE follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followed_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followed_id),
+    CHECK (follower_id <> followed_id)
+);
+
+CREATE INDEX follows_followed_id_idx ON follows (followed_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS likes;
+DROP INDEX IF EXISTS chirps_reply_to_id_idx;
+ALTER TABLE chirps DROP COLUMN IF EXISTS reply_to_id;
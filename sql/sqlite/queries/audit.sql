@@ -0,0 +1,17 @@
+-- name: CreateAuditLog :one
+INSERT INTO admin_audit_log (id, created_at, actor_id, action, target_type, target_id, details)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(now),
+    sqlc.arg(actor_id),
+    sqlc.arg(action),
+    sqlc.arg(target_type),
+    sqlc.arg(target_id),
+    sqlc.arg(details)
+)
+RETURNING *;
+
+-- name: ListAuditLogs :many
+SELECT * FROM admin_audit_log
+ORDER BY created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
@@ -0,0 +1,24 @@
+-- name: BlockUser :exec
+INSERT INTO blocks (blocker_id, blocked_id, created_at)
+VALUES (sqlc.arg(blocker_id), sqlc.arg(blocked_id), sqlc.arg(now))
+ON CONFLICT DO NOTHING;
+
+-- name: UnblockUser :exec
+DELETE FROM blocks
+WHERE blocker_id = sqlc.arg(blocker_id) AND blocked_id = sqlc.arg(blocked_id);
+
+-- name: ListBlocks :many
+SELECT * FROM blocks
+WHERE blocker_id = sqlc.arg(blocker_id)
+ORDER BY created_at DESC;
+
+-- name: ListBlockers :many
+SELECT * FROM blocks
+WHERE blocked_id = sqlc.arg(blocked_id)
+ORDER BY created_at DESC;
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocker_id = sqlc.arg(blocker_id) AND blocked_id = sqlc.arg(blocked_id)
+) AS blocked;
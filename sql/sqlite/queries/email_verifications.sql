@@ -0,0 +1,20 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (token, created_at, user_id, email, expires_at)
+VALUES (
+    sqlc.arg(token),
+    sqlc.arg(now),
+    sqlc.arg(user_id),
+    sqlc.arg(email),
+    sqlc.arg(expires_at)
+)
+RETURNING *;
+
+-- name: GetPendingEmailVerification :one
+SELECT * FROM email_verifications
+WHERE token = sqlc.arg(token)
+AND used_at IS NULL
+AND expires_at > sqlc.arg(now);
+
+-- name: MarkEmailVerificationUsed :exec
+UPDATE email_verifications SET used_at = sqlc.arg(now)
+WHERE token = sqlc.arg(token);
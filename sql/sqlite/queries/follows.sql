@@ -0,0 +1,23 @@
+-- name: FollowUser :execrows
+INSERT INTO follows (follower_id, followed_id, created_at)
+VALUES (sqlc.arg(follower_id), sqlc.arg(followed_id), sqlc.arg(now))
+ON CONFLICT DO NOTHING;
+
+-- name: UnfollowUser :exec
+DELETE FROM follows
+WHERE follower_id = sqlc.arg(follower_id) AND followed_id = sqlc.arg(followed_id);
+
+-- name: ListFollowing :many
+SELECT * FROM follows
+WHERE follower_id = sqlc.arg(follower_id)
+ORDER BY created_at DESC;
+
+-- name: ListFollowers :many
+SELECT * FROM follows
+WHERE followed_id = sqlc.arg(followed_id)
+ORDER BY created_at DESC;
+
+-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg(user_a) AND followed_id = sqlc.arg(user_b))
+OR (follower_id = sqlc.arg(user_b) AND followed_id = sqlc.arg(user_a));
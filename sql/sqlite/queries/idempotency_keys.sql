@@ -0,0 +1,41 @@
+-- name: CreateIdempotencyKey :execrows
+INSERT INTO idempotency_keys (scope, key, fingerprint, created_at, expires_at)
+VALUES (
+    sqlc.arg(scope),
+    sqlc.arg(key),
+    sqlc.arg(fingerprint),
+    sqlc.arg(now),
+    sqlc.arg(expires_at)
+)
+ON CONFLICT (scope, key) DO UPDATE
+SET fingerprint = excluded.fingerprint,
+    status_code = 0,
+    content_type = '',
+    response_body = x'',
+    created_at = excluded.created_at,
+    expires_at = excluded.expires_at
+WHERE idempotency_keys.expires_at <= excluded.created_at;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE scope = sqlc.arg(scope)
+AND key = sqlc.arg(key)
+AND expires_at > sqlc.arg(now);
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = sqlc.arg(status_code),
+    content_type = sqlc.arg(content_type),
+    response_body = sqlc.arg(response_body),
+    expires_at = sqlc.arg(expires_at)
+WHERE scope = sqlc.arg(scope)
+AND key = sqlc.arg(key);
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE scope = sqlc.arg(scope)
+AND key = sqlc.arg(key);
+
+-- name: PurgeExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= sqlc.arg(now);
@@ -0,0 +1,13 @@
+-- name: LikeChirp :execrows
+INSERT INTO likes (user_id, chirp_id, created_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(chirp_id), sqlc.arg(now))
+ON CONFLICT DO NOTHING;
+
+-- name: UnlikeChirp :exec
+DELETE FROM likes
+WHERE user_id = sqlc.arg(user_id) AND chirp_id = sqlc.arg(chirp_id);
+
+-- name: ListLikesByUser :many
+SELECT * FROM likes
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC;
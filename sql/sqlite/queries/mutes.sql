@@ -0,0 +1,13 @@
+-- name: MuteUser :exec
+INSERT INTO mutes (muter_id, muted_id, created_at)
+VALUES (sqlc.arg(muter_id), sqlc.arg(muted_id), sqlc.arg(now))
+ON CONFLICT DO NOTHING;
+
+-- name: UnmuteUser :exec
+DELETE FROM mutes
+WHERE muter_id = sqlc.arg(muter_id) AND muted_id = sqlc.arg(muted_id);
+
+-- name: ListMutes :many
+SELECT * FROM mutes
+WHERE muter_id = sqlc.arg(muter_id)
+ORDER BY created_at DESC;
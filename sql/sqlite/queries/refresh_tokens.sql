@@ -0,0 +1,33 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at)
+VALUES (
+    sqlc.arg(token),
+    sqlc.arg(now),
+    sqlc.arg(now),
+    sqlc.arg(user_id),
+    sqlc.arg(expires_at)
+)
+RETURNING *;
+
+-- name: RevokeRefreshToken :one
+UPDATE refresh_tokens SET revoked_at = sqlc.arg(now),
+updated_at = sqlc.arg(now)
+WHERE token = sqlc.arg(token)
+RETURNING *;
+
+-- name: GetUserFromRefreshToken :one
+SELECT users.* FROM users
+JOIN refresh_tokens ON users.id = refresh_tokens.user_id
+WHERE refresh_tokens.token = sqlc.arg(token)
+AND revoked_at IS NULL
+AND expires_at > sqlc.arg(now);
+
+-- name: ListRefreshTokensForUser :many
+SELECT * FROM refresh_tokens
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at ASC;
+
+-- name: RevokeAllRefreshTokensForUser :exec
+UPDATE refresh_tokens SET revoked_at = sqlc.arg(now),
+updated_at = sqlc.arg(now)
+WHERE user_id = sqlc.arg(user_id) AND revoked_at IS NULL;
@@ -0,0 +1,28 @@
+-- name: CreateReport :one
+INSERT INTO reports (id, created_at, updated_at, reporter_id, reported_user_id, chirp_id, reason, details)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(now),
+    sqlc.arg(now),
+    sqlc.arg(reporter_id),
+    sqlc.arg(reported_user_id),
+    sqlc.arg(chirp_id),
+    sqlc.arg(reason),
+    sqlc.arg(details)
+)
+RETURNING *;
+
+-- name: GetReport :one
+SELECT * FROM reports
+WHERE id = sqlc.arg(id);
+
+-- name: ListReports :many
+SELECT * FROM reports
+WHERE status = sqlc.arg(status)
+ORDER BY created_at ASC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ResolveReport :one
+UPDATE reports SET status = 'resolved', resolution = sqlc.arg(resolution), resolved_by = sqlc.arg(resolved_by), resolved_at = sqlc.arg(now), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND status = 'open'
+RETURNING *;
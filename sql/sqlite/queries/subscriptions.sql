@@ -0,0 +1,15 @@
+-- name: CreateSubscriptionEvent :one
+INSERT INTO subscription_events (id, created_at, user_id, event, source)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(now),
+    sqlc.arg(user_id),
+    sqlc.arg(event),
+    sqlc.arg(source)
+)
+RETURNING *;
+
+-- name: ListSubscriptionEvents :many
+SELECT * FROM subscription_events
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at ASC;
@@ -0,0 +1,101 @@
+-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, username, display_name)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(now),
+    sqlc.arg(now),
+    sqlc.arg(email),
+    sqlc.arg(hashed_password),
+    sqlc.arg(username),
+    sqlc.arg(display_name)
+)
+RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = sqlc.arg(email);
+
+-- name: GetUserByUsername :one
+SELECT * FROM users
+WHERE LOWER(username) = LOWER(sqlc.arg(username)) AND deleted_at IS NULL;
+
+-- name: UpdateUserProfile :one
+UPDATE users SET username = sqlc.arg(username), display_name = sqlc.arg(display_name), bio = sqlc.arg(bio), avatar_url = sqlc.arg(avatar_url), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateUser :one
+UPDATE users SET email = sqlc.arg(email), hashed_password = sqlc.arg(hashed_password), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: PatchUser :one
+UPDATE users SET
+    hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
+    username = COALESCE(sqlc.narg(username), username),
+    display_name = COALESCE(sqlc.narg(display_name), display_name),
+    bio = COALESCE(sqlc.narg(bio), bio),
+    avatar_url = COALESCE(sqlc.narg(avatar_url), avatar_url),
+    updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateUserEmail :one
+UPDATE users SET email = sqlc.arg(email), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpgradeToChirpyRed :one
+UPDATE users SET is_chirpy_red = TRUE, updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = sqlc.arg(id);
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE CAST(sqlc.arg(query) AS TEXT) = ''
+   OR email LIKE '%' || replace(replace(replace(CAST(sqlc.arg(query) AS TEXT), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+ORDER BY created_at ASC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: SetUserRole :one
+UPDATE users SET role = sqlc.arg(role), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SuspendUser :one
+UPDATE users SET suspended_until = sqlc.arg(suspended_until), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: BanUser :one
+UPDATE users SET banned_at = sqlc.arg(now), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ReinstateUser :one
+UPDATE users SET suspended_until = NULL, banned_at = NULL, updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetChirpyRed :one
+UPDATE users SET is_chirpy_red = sqlc.arg(is_chirpy_red), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SoftDeleteUser :one
+UPDATE users SET deleted_at = sqlc.arg(now), purge_after = sqlc.arg(purge_after), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CancelUserDeletion :one
+UPDATE users SET deleted_at = NULL, purge_after = NULL, updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND purge_after <= sqlc.arg(now);
@@ -0,0 +1,8 @@
+-- +goose Up
+UPDATE users SET email = LOWER(email) WHERE email <> LOWER(email);
+UPDATE email_verifications SET email = LOWER(email) WHERE email <> LOWER(email);
+
+CREATE UNIQUE INDEX users_email_lower_idx ON users (LOWER(email));
+
+-- +goose Down
+DROP INDEX IF EXISTS users_email_lower_idx;
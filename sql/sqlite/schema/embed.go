@@ -0,0 +1,9 @@
+// Package schema incrusta las migraciones de goose para SQLite.
+package schema
+
+import "embed"
+
+// FS contiene los archivos NNN_nombre.sql de este directorio.
+//
+//go:embed *.sql
+var FS embed.FS